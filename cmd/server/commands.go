@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moynur/news-app/internal/config"
+	"github.com/moynur/news-app/internal/migrate"
+	"github.com/moynur/news-app/internal/service"
+	"github.com/moynur/news-app/internal/store"
+)
+
+const usage = `usage:
+  app                        run the api and the feeder
+  app opml import <file>     add the feeds in an OPML file
+  app opml export [file]     write every feed as OPML, to stdout when no file is given
+  app migrate up             apply every migration which hasn't been applied
+  app migrate down [steps]   revert the latest migrations, one when steps isn't given
+  app migrate status         list the migrations and when they were applied`
+
+// runCommand runs one of the subcommands instead of the server
+func runCommand(args []string) error {
+	switch {
+	case len(args) == 3 && args[0] == "opml" && args[1] == "import":
+		return importOPML(args[2])
+	case len(args) >= 2 && len(args) <= 3 && args[0] == "opml" && args[1] == "export":
+		file := ""
+		if len(args) == 3 {
+			file = args[2]
+		}
+		return exportOPML(file)
+	case len(args) == 2 && args[0] == "migrate" && args[1] == "up":
+		return migrateCommand(func(ctx context.Context, migrator *migrate.Migrator) error {
+			_, err := migrateUp(ctx, migrator)
+			return err
+		})
+	case len(args) >= 2 && len(args) <= 3 && args[0] == "migrate" && args[1] == "down":
+		steps := 1
+		if len(args) == 3 {
+			var err error
+			steps, err = strconv.Atoi(args[2])
+			if err != nil || steps <= 0 {
+				return fmt.Errorf("steps must be a positive number\n%s", usage)
+			}
+		}
+		return migrateCommand(func(ctx context.Context, migrator *migrate.Migrator) error {
+			return migrateDown(ctx, migrator, steps)
+		})
+	case len(args) == 2 && args[0] == "migrate" && args[1] == "status":
+		return migrateCommand(migrateStatus)
+	}
+	return fmt.Errorf("unknown command %q\n%s", strings.Join(args, " "), usage)
+}
+
+// openStore connects to the store the config chooses, migrating the database when that's configured
+func openStore(cfg config.DatabaseConfig) (store.Storer, error) {
+	if cfg.Driver == config.DriverMemory {
+		log.Println("using the in memory store, nothing will be kept once the service stops")
+		return store.NewMemoryStore(), nil
+	}
+	db, err := connectStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.MigrateOnStart {
+		err = migrateStore(context.Background(), db)
+	}
+	return db, err
+}
+
+// connectStore connects to the database the config chooses, which has to be one with migrations
+func connectStore(cfg config.DatabaseConfig) (*store.Store, error) {
+	if cfg.Driver == config.DriverSQLite {
+		return store.NewSQLiteStore(cfg.Path)
+	}
+	return store.NewStore(cfg)
+}
+
+// loadStore opens the store for the subcommands, which use the same config as the server
+func loadStore() (store.Storer, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	return openStore(cfg.Database)
+}
+
+// migrateStore applies any migrations the database is missing
+func migrateStore(ctx context.Context, db *store.Store) error {
+	migrator, err := db.Migrator()
+	if err != nil {
+		return err
+	}
+	_, err = migrateUp(ctx, migrator)
+	return err
+}
+
+func migrateCommand(run func(ctx context.Context, migrator *migrate.Migrator) error) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if cfg.Database.Driver == config.DriverMemory {
+		return fmt.Errorf("the memory driver doesn't have migrations")
+	}
+	db, err := connectStore(cfg.Database)
+	if err != nil {
+		return err
+	}
+	migrator, err := db.Migrator()
+	if err != nil {
+		return err
+	}
+	return run(context.Background(), migrator)
+}
+
+func migrateUp(ctx context.Context, migrator *migrate.Migrator) ([]migrate.Migration, error) {
+	applied, err := migrator.Up(ctx)
+	for _, migration := range applied {
+		log.Printf("applied migration %d_%s\n", migration.Version, migration.Name)
+	}
+	if err != nil {
+		return applied, err
+	}
+	if len(applied) == 0 {
+		log.Println("the database is up to date")
+	}
+	return applied, nil
+}
+
+func migrateDown(ctx context.Context, migrator *migrate.Migrator, steps int) error {
+	reverted, err := migrator.Down(ctx, steps)
+	for _, migration := range reverted {
+		fmt.Printf("reverted %d_%s\n", migration.Version, migration.Name)
+	}
+	return err
+}
+
+func migrateStatus(ctx context.Context, migrator *migrate.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		applied := "pending"
+		if status.AppliedAt != nil {
+			applied = "applied " + status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Printf("%d_%s %s\n", status.Version, status.Name, applied)
+	}
+	return nil
+}
+
+func importOPML(file string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	// the feeds would only be kept until the command exits
+	if cfg.Database.Driver == config.DriverMemory {
+		return fmt.Errorf("the memory driver can't keep imported feeds, import them into postgres or sqlite")
+	}
+	db, err := openStore(cfg.Database)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("unable to open opml file, %w", err)
+	}
+	defer f.Close()
+
+	result, err := service.NewService(db, nil).ImportOPML(context.Background(), f)
+	if err != nil {
+		return err
+	}
+	for _, feed := range result.Created {
+		fmt.Printf("created %s %s\n", feed.Name, feed.URL)
+	}
+	for _, url := range result.Skipped {
+		fmt.Printf("skipped %s, it has already been added\n", url)
+	}
+	for _, failure := range result.Failed {
+		fmt.Printf("failed %s, %s\n", failure.URL, failure.Reason)
+	}
+	fmt.Printf("%d created, %d skipped, %d failed\n", len(result.Created), len(result.Skipped), len(result.Failed))
+	return nil
+}
+
+func exportOPML(file string) error {
+	db, err := loadStore()
+	if err != nil {
+		return err
+	}
+	var w io.Writer = os.Stdout
+	if file != "" {
+		f, err := os.Create(file)
+		if err != nil {
+			return fmt.Errorf("unable to create opml file, %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	return service.NewService(db, nil).ExportOPML(context.Background(), w)
+}
@@ -1,14 +1,20 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"errors"
 	"github.com/moynur/news-app/internal/config"
 	feeder "github.com/moynur/news-app/internal/feed"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/moynur/news-app/internal/service"
-	"github.com/moynur/news-app/internal/store"
 	"github.com/moynur/news-app/internal/transport/http"
 
 	"github.com/gorilla/mux"
@@ -25,35 +31,94 @@ type ResponseWriter interface {
 }
 
 func main() {
-	db, err := store.NewStore()
-	if err != nil {
-		log.Println(err)
+	if len(os.Args) > 1 {
+		err := runCommand(os.Args[1:])
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
 	}
+	serve()
+}
+
+// serve runs the api and the feeder until the process is told to stop
+func serve() {
 	cfg, err := config.Load()
 	if err != nil {
 		log.Fatalf("err loading config %e", err)
 	}
-	svc := service.NewService(db)
-	feeders := feeder.NewFeeder(db, cfg.Client)
-	feeders.LoadAndStoreArticles()
-	go feeders.RefreshArticles(60)
-	client, _ := handler.NewHandler(svc)
+	db, err := openStore(cfg.Database)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	// work in progress is only cancelled once the shutdown deadline has passed, until then it is left to finish
+	runCtx, cancelRun := context.WithCancel(context.Background())
+	defer cancelRun()
+
+	svc := service.NewService(db, cursorSecret(cfg.Server))
+	feeders := feeder.NewFeeder(db, cfg.Feeder)
+	err = feeders.SeedFeeds(ctx, cfg.Feeds)
+	if err != nil {
+		log.Println(err)
+	}
+	refreshDone := make(chan struct{})
+	go func() {
+		feeders.RefreshArticles(runCtx)
+		close(refreshDone)
+	}()
+	client, _ := handler.NewHandler(svc, cfg.Admin.Token)
 
 	r := mux.NewRouter()
 	client.ApplyRoutes(r)
 
-	// should be as a config
-	address := "0.0.0.0:8081"
-
 	srv := &http.Server{
-		Addr:              address,
+		Addr:              cfg.Server.Address,
 		Handler:           r,
 		ReadTimeout:       1 * time.Second,
 		ReadHeaderTimeout: 1 * time.Second,
 		WriteTimeout:      1 * time.Second,
 		IdleTimeout:       1 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return runCtx
+		},
 	}
 
-	log.Fatal(srv.ListenAndServe())
+	go func() {
+		err := srv.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("shutting down, waiting for requests and feeds to finish")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	err = srv.Shutdown(shutdownCtx)
+	if err != nil {
+		log.Println("requests did not finish in time", err)
+	}
+	err = feeders.Shutdown(shutdownCtx)
+	if err != nil {
+		log.Println("feeds did not finish in time", err)
+	}
+	cancelRun()
+	<-refreshDone
+	log.Println("shut down")
+}
 
+// cursorSecret is the configured secret for sealing cursors, or a random one when there isn't one
+func cursorSecret(cfg config.ServerConfig) []byte {
+	if cfg.CursorSecret != "" {
+		return []byte(cfg.CursorSecret)
+	}
+	log.Println("no cursor secret is set, cursors will stop working when the service restarts and won't work on other replicas")
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		log.Fatalf("unable to make a cursor secret %v", err)
+	}
+	return secret
 }
@@ -0,0 +1,77 @@
+package canonical
+
+import (
+	"net/url"
+	"strings"
+)
+
+// trackingParams are query parameters added for analytics which never change the page they link to
+var trackingParams = map[string]bool{
+	"fbclid":      true,
+	"gclid":       true,
+	"dclid":       true,
+	"msclkid":     true,
+	"yclid":       true,
+	"igshid":      true,
+	"mc_cid":      true,
+	"mc_eid":      true,
+	"_ga":         true,
+	"at_medium":   true,
+	"at_campaign": true,
+	"at_custom1":  true,
+	"at_custom2":  true,
+	"at_custom3":  true,
+	"at_custom4":  true,
+}
+
+// URL cleans up a link so the same page is always linked the same way. The scheme and host are lower-cased, default
+// ports, fragments and tracking parameters are removed and the rest of the query is sorted. Anything which isn't an
+// absolute http or https url is returned as it was
+func URL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" {
+		return raw
+	}
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return raw
+	}
+	host := strings.ToLower(parsed.Hostname())
+	port := parsed.Port()
+	if (parsed.Scheme == "http" && port == "80") || (parsed.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	parsed.Host = host
+	if port != "" {
+		parsed.Host = host + ":" + port
+	}
+	parsed.Fragment = ""
+	parsed.RawFragment = ""
+	if parsed.Path == "" {
+		parsed.Path = "/"
+	}
+	query := parsed.Query()
+	for name := range query {
+		if strings.HasPrefix(strings.ToLower(name), "utm_") || trackingParams[strings.ToLower(name)] {
+			query.Del(name)
+		}
+	}
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
+
+// Key is what links are de-duplicated on, it ignores the scheme and any trailing slash as publishers serve the same
+// page with and without them
+func Key(raw string) string {
+	link := URL(raw)
+	parsed, err := url.Parse(link)
+	if err != nil || parsed.Host == "" {
+		return link
+	}
+	key := parsed.Host + strings.TrimRight(parsed.EscapedPath(), "/")
+	if parsed.RawQuery != "" {
+		key += "?" + parsed.RawQuery
+	}
+	return key
+}
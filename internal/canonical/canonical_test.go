@@ -0,0 +1,46 @@
+package canonical_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moynur/news-app/internal/canonical"
+)
+
+func TestURL(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{name: "already canonical", raw: "https://news.sky.com/story/1", want: "https://news.sky.com/story/1"},
+		{name: "host is lower-cased", raw: "HTTPS://News.Sky.COM/story/Some-Story", want: "https://news.sky.com/story/Some-Story"},
+		{name: "tracking parameters are removed", raw: "https://news.sky.com/story/1?utm_source=twitter&utm_medium=social&fbclid=abc", want: "https://news.sky.com/story/1"},
+		{name: "other parameters are kept and sorted", raw: "https://example.com/a?page=2&id=7&utm_campaign=x", want: "https://example.com/a?id=7&page=2"},
+		{name: "default port and fragment are removed", raw: "https://example.com:443/a#comments", want: "https://example.com/a"},
+		{name: "other ports are kept", raw: "http://example.com:8080/a", want: "http://example.com:8080/a"},
+		{name: "empty path", raw: "https://example.com", want: "https://example.com/"},
+		{name: "whitespace", raw: "  https://example.com/a ", want: "https://example.com/a"},
+		{name: "not a url", raw: "urn:uuid:1225c695", want: "urn:uuid:1225c695"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, canonical.URL(tt.raw))
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	want := "news.sky.com/story/1"
+	for _, raw := range []string{
+		"https://news.sky.com/story/1",
+		"http://news.sky.com/story/1",
+		"https://news.sky.com/story/1/",
+		"https://NEWS.sky.com/story/1?utm_source=rss#top",
+	} {
+		assert.Equal(t, want, canonical.Key(raw), raw)
+	}
+	assert.Equal(t, "example.com/a?id=7", canonical.Key("https://example.com/a/?id=7"))
+	assert.NotEqual(t, canonical.Key("https://example.com/a?id=7"), canonical.Key("https://example.com/a?id=8"))
+}
@@ -4,14 +4,147 @@ import (
 	"fmt"
 	"gopkg.in/yaml.v3"
 	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 const (
-	defaultConfigFile = "config.yaml"
+	defaultConfigFile      = "config.yaml"
+	defaultRefreshInterval = 60 * time.Second
+	defaultUserAgent       = "news-app/1.0 (+https://github.com/moynur/news-app)"
+	defaultFetchTimeout    = 30 * time.Second
+	defaultRetryBaseDelay  = 30 * time.Second
+	defaultRetryMaxDelay   = time.Hour
+	defaultMaxFailures     = 10
+	defaultMaxPageFetches  = 10
+	defaultReloadInterval  = 30 * time.Second
+	defaultAddress         = "0.0.0.0:8081"
+	defaultShutdownTimeout = 15 * time.Second
+	DriverPostgres         = "postgres"
+	DriverMemory           = "memory"
+	DriverSQLite           = "sqlite"
+	defaultDBHost          = "localhost"
+	defaultDBPort          = 5432
+	defaultDBUser          = "postgres"
+	defaultDBName          = "postgres"
+	defaultDBSSLMode       = "prefer"
+	defaultDBPoolSize      = 10
+	defaultDBConnLifetime  = 30 * time.Minute
+	defaultDBConnectTime   = 10 * time.Second
+	defaultDBPath          = "news.db"
 )
 
+// sslModes are the sslmode values Postgres understands
+var sslModes = map[string]bool{
+	"disable":     true,
+	"allow":       true,
+	"prefer":      true,
+	"require":     true,
+	"verify-ca":   true,
+	"verify-full": true,
+}
+
 type ServiceConfig struct {
-	Client string `yaml:"rss_feed_url"`
+	Server   ServerConfig   `yaml:"server"`
+	Database DatabaseConfig `yaml:"database"`
+	Admin    AdminConfig    `yaml:"admin"`
+	Feeder   FeederConfig   `yaml:"feeder"`
+	// Feeds are added the first time the service starts, after that they are managed through the admin API
+	Feeds []FeedConfig `yaml:"feeds"`
+}
+
+type AdminConfig struct {
+	// Token has to be sent as a bearer token to use the admin API, the admin API is disabled without one.
+	// It can be set with the ADMIN_TOKEN environment variable so it doesn't need to be kept in the config file
+	Token string `yaml:"token"`
+}
+
+type ServerConfig struct {
+	Address string `yaml:"address"`
+	// ShutdownTimeout is how long in-flight requests and feed polls have to finish once the service is told to stop
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// CursorSecret seals the pagination cursors given to clients, every replica needs the same one. It can be set with
+	// the CURSOR_SECRET environment variable, without one a random secret is used until the service restarts
+	CursorSecret string `yaml:"cursor_secret"`
+}
+
+// DatabaseConfig is how to connect to the database, every setting can be overridden with a DB_ environment variable so
+// the same config file can be used in every environment
+type DatabaseConfig struct {
+	// Driver is postgres (the default), sqlite for a single node, or memory which keeps everything in memory until the
+	// service stops
+	Driver string `yaml:"driver"`
+	// Path is the SQLite database file, it's created when it doesn't exist. The rest of the settings are for Postgres
+	Path string `yaml:"path"`
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+	User string `yaml:"user"`
+	// PasswordFile is read for the password so it can be mounted as a secret, without one DB_PASSWORD is used
+	PasswordFile string `yaml:"password_file"`
+	Password     string `yaml:"-"`
+	Name         string `yaml:"name"`
+	SSLMode      string `yaml:"sslmode"`
+	// PoolSize is the most connections which will be open at once
+	PoolSize        int           `yaml:"pool_size"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	// StatementTimeout stops any query which runs for longer, zero leaves it to the server
+	StatementTimeout time.Duration `yaml:"statement_timeout"`
+	ConnectTimeout   time.Duration `yaml:"connect_timeout"`
+	// MigrateOnStart applies any migrations the database is missing when the service starts, without it they have to
+	// be applied with the migrate command
+	MigrateOnStart bool `yaml:"migrate_on_start"`
+}
+
+// DSN is the connection string for the database, values are quoted so a password can contain anything
+func (d DatabaseConfig) DSN() string {
+	settings := []string{
+		"host=" + quoteDSN(d.Host),
+		"port=" + strconv.Itoa(d.Port),
+		"user=" + quoteDSN(d.User),
+		"password=" + quoteDSN(d.Password),
+		"dbname=" + quoteDSN(d.Name),
+		"sslmode=" + quoteDSN(d.SSLMode),
+		"connect_timeout=" + strconv.Itoa(int(d.ConnectTimeout.Seconds())),
+	}
+	if d.StatementTimeout > 0 {
+		settings = append(settings, "statement_timeout="+strconv.FormatInt(d.StatementTimeout.Milliseconds(), 10))
+	}
+	return strings.Join(settings, " ")
+}
+
+func quoteDSN(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, "'", `\'`)
+	return "'" + value + "'"
+}
+
+// FeederConfig is how the feeder should behave when talking to any publisher
+type FeederConfig struct {
+	UserAgent    string        `yaml:"user_agent"`
+	FetchTimeout time.Duration `yaml:"fetch_timeout"`
+	// a failing feed is retried after RetryBaseDelay, doubling each time it fails again up to RetryMaxDelay
+	RetryBaseDelay time.Duration `yaml:"retry_base_delay"`
+	RetryMaxDelay  time.Duration `yaml:"retry_max_delay"`
+	// MaxConsecutiveFailures is how many times in a row a feed can fail before it is paused
+	MaxConsecutiveFailures int `yaml:"max_consecutive_failures"`
+	// MaxPageFetches is how many new articles' pages are read each time a feed is polled, they all have to be read
+	// within FetchTimeout
+	MaxPageFetches int `yaml:"max_page_fetches"`
+	// ReloadInterval is how often the feeder checks for feeds which have been added, changed or removed
+	ReloadInterval time.Duration `yaml:"reload_interval"`
+}
+
+// FeedConfig describes a single RSS/Atom source the feeder should poll
+type FeedConfig struct {
+	Name            string        `yaml:"name"`
+	URL             string        `yaml:"url"`
+	RefreshInterval time.Duration `yaml:"refresh_interval"`
+	DefaultCategory string        `yaml:"default_category"`
+	// FallbackImage is the thumbnail given to articles when no image can be found for them
+	FallbackImage string `yaml:"fallback_image"`
+	Enabled       bool   `yaml:"enabled"`
 }
 
 func Load() (ServiceConfig, error) {
@@ -24,6 +157,198 @@ func Load() (ServiceConfig, error) {
 	if err != nil {
 		return ServiceConfig{}, fmt.Errorf("unable to parse config file: %w", err)
 	}
+	err = cfg.validate()
+	if err != nil {
+		return ServiceConfig{}, fmt.Errorf("invalid config file: %w", err)
+	}
 
 	return cfg, nil
 }
+
+// validate checks every feed can be polled and fills in any defaults that were left out
+func (c *ServiceConfig) validate() error {
+	if c.Server.Address == "" {
+		c.Server.Address = defaultAddress
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		c.Server.ShutdownTimeout = defaultShutdownTimeout
+	}
+	if c.Feeder.UserAgent == "" {
+		c.Feeder.UserAgent = defaultUserAgent
+	}
+	if c.Feeder.FetchTimeout <= 0 {
+		c.Feeder.FetchTimeout = defaultFetchTimeout
+	}
+	if c.Feeder.RetryBaseDelay <= 0 {
+		c.Feeder.RetryBaseDelay = defaultRetryBaseDelay
+	}
+	if c.Feeder.RetryMaxDelay < c.Feeder.RetryBaseDelay {
+		c.Feeder.RetryMaxDelay = defaultRetryMaxDelay
+	}
+	if c.Feeder.MaxConsecutiveFailures <= 0 {
+		c.Feeder.MaxConsecutiveFailures = defaultMaxFailures
+	}
+	if c.Feeder.MaxPageFetches <= 0 {
+		c.Feeder.MaxPageFetches = defaultMaxPageFetches
+	}
+	if c.Feeder.ReloadInterval <= 0 {
+		c.Feeder.ReloadInterval = defaultReloadInterval
+	}
+	c.Admin.Token = envOr("ADMIN_TOKEN", c.Admin.Token)
+	c.Server.CursorSecret = envOr("CURSOR_SECRET", c.Server.CursorSecret)
+	err := c.Database.validate()
+	if err != nil {
+		return fmt.Errorf("invalid database config: %w", err)
+	}
+	names := make(map[string]bool, len(c.Feeds))
+	for i := range c.Feeds {
+		feed := &c.Feeds[i]
+		if feed.Name == "" {
+			return fmt.Errorf("feed %d is missing a name", i)
+		}
+		if feed.URL == "" {
+			return fmt.Errorf("feed %q is missing a url", feed.Name)
+		}
+		if names[feed.Name] {
+			return fmt.Errorf("feed %q is configured more than once", feed.Name)
+		}
+		names[feed.Name] = true
+		if feed.RefreshInterval <= 0 {
+			feed.RefreshInterval = defaultRefreshInterval
+		}
+	}
+	return nil
+}
+
+// validate applies the DB_ environment variables and fills in defaults for anything which is still missing
+func (d *DatabaseConfig) validate() error {
+	d.Driver = envOr("DB_DRIVER", d.Driver)
+	d.Path = envOr("DB_PATH", d.Path)
+	d.Host = envOr("DB_HOST", d.Host)
+	d.User = envOr("DB_USERNAME", d.User)
+	d.Name = envOr("DB_DB", d.Name)
+	d.SSLMode = envOr("DB_SSLMODE", d.SSLMode)
+	d.PasswordFile = envOr("DB_PASSWORD_FILE", d.PasswordFile)
+	var err error
+	d.Port, err = envIntOr("DB_PORT", d.Port)
+	if err != nil {
+		return err
+	}
+	d.PoolSize, err = envIntOr("DB_POOL_SIZE", d.PoolSize)
+	if err != nil {
+		return err
+	}
+	d.ConnMaxLifetime, err = envDurationOr("DB_CONN_MAX_LIFETIME", d.ConnMaxLifetime)
+	if err != nil {
+		return err
+	}
+	d.StatementTimeout, err = envDurationOr("DB_STATEMENT_TIMEOUT", d.StatementTimeout)
+	if err != nil {
+		return err
+	}
+	d.ConnectTimeout, err = envDurationOr("DB_CONNECT_TIMEOUT", d.ConnectTimeout)
+	if err != nil {
+		return err
+	}
+	d.MigrateOnStart, err = envBoolOr("DB_MIGRATE_ON_START", d.MigrateOnStart)
+	if err != nil {
+		return err
+	}
+	if d.PasswordFile != "" {
+		password, err := ioutil.ReadFile(d.PasswordFile)
+		if err != nil {
+			return fmt.Errorf("unable to read password file: %w", err)
+		}
+		d.Password = strings.TrimRight(string(password), "\r\n")
+	} else {
+		d.Password = envOr("DB_PASSWORD", d.Password)
+	}
+
+	switch d.Driver {
+	case "":
+		d.Driver = DriverPostgres
+	case DriverPostgres, DriverMemory, DriverSQLite:
+	default:
+		return fmt.Errorf("unknown driver %q", d.Driver)
+	}
+	if d.Path == "" {
+		d.Path = defaultDBPath
+	}
+	if d.Host == "" {
+		d.Host = defaultDBHost
+	}
+	if d.Port == 0 {
+		d.Port = defaultDBPort
+	}
+	if d.Port < 0 || d.Port > 65535 {
+		return fmt.Errorf("port %d is out of range", d.Port)
+	}
+	if d.User == "" {
+		d.User = defaultDBUser
+	}
+	if d.Name == "" {
+		d.Name = defaultDBName
+	}
+	if d.SSLMode == "" {
+		d.SSLMode = defaultDBSSLMode
+	}
+	if !sslModes[d.SSLMode] {
+		return fmt.Errorf("unknown sslmode %q", d.SSLMode)
+	}
+	if d.PoolSize <= 0 {
+		d.PoolSize = defaultDBPoolSize
+	}
+	if d.ConnMaxLifetime <= 0 {
+		d.ConnMaxLifetime = defaultDBConnLifetime
+	}
+	if d.StatementTimeout < 0 {
+		return fmt.Errorf("statement timeout can't be negative")
+	}
+	if d.ConnectTimeout < time.Second {
+		d.ConnectTimeout = defaultDBConnectTime
+	}
+	return nil
+}
+
+func envIntOr(key string, fallback int) (int, error) {
+	value := envOr(key, "")
+	if value == "" {
+		return fallback, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number: %w", key, err)
+	}
+	return parsed, nil
+}
+
+func envDurationOr(key string, fallback time.Duration) (time.Duration, error) {
+	value := envOr(key, "")
+	if value == "" {
+		return fallback, nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a duration: %w", key, err)
+	}
+	return parsed, nil
+}
+
+func envBoolOr(key string, fallback bool) (bool, error) {
+	value := envOr(key, "")
+	if value == "" {
+		return fallback, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false: %w", key, err)
+	}
+	return parsed, nil
+}
+
+func envOr(key string, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return fallback
+}
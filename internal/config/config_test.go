@@ -0,0 +1,102 @@
+package config
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatabaseConfig_validate(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		var cfg DatabaseConfig
+		require.NoError(t, cfg.validate())
+		assert.Equal(t, DatabaseConfig{
+			Driver:          DriverPostgres,
+			Path:            defaultDBPath,
+			Host:            defaultDBHost,
+			Port:            defaultDBPort,
+			User:            defaultDBUser,
+			Name:            defaultDBName,
+			SSLMode:         defaultDBSSLMode,
+			PoolSize:        defaultDBPoolSize,
+			ConnMaxLifetime: defaultDBConnLifetime,
+			ConnectTimeout:  defaultDBConnectTime,
+		}, cfg)
+	})
+
+	t.Run("environment overrides the file", func(t *testing.T) {
+		t.Setenv("DB_HOST", "staging-db")
+		t.Setenv("DB_PORT", "6432")
+		t.Setenv("DB_USERNAME", "news")
+		t.Setenv("DB_PASSWORD", "secret")
+		t.Setenv("DB_DB", "news")
+		t.Setenv("DB_SSLMODE", "require")
+		t.Setenv("DB_POOL_SIZE", "25")
+		t.Setenv("DB_STATEMENT_TIMEOUT", "5s")
+		t.Setenv("DB_DRIVER", "sqlite")
+		t.Setenv("DB_PATH", "/var/lib/news/news.db")
+		cfg := DatabaseConfig{Host: "db", Port: 5432, PoolSize: 5}
+		require.NoError(t, cfg.validate())
+		assert.Equal(t, "staging-db", cfg.Host)
+		assert.Equal(t, 6432, cfg.Port)
+		assert.Equal(t, "news", cfg.User)
+		assert.Equal(t, "secret", cfg.Password)
+		assert.Equal(t, "news", cfg.Name)
+		assert.Equal(t, "require", cfg.SSLMode)
+		assert.Equal(t, 25, cfg.PoolSize)
+		assert.Equal(t, 5*time.Second, cfg.StatementTimeout)
+		assert.Equal(t, DriverSQLite, cfg.Driver)
+		assert.Equal(t, "/var/lib/news/news.db", cfg.Path)
+	})
+
+	t.Run("password file", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "password")
+		require.NoError(t, ioutil.WriteFile(file, []byte("from-file\n"), 0600))
+		t.Setenv("DB_PASSWORD", "from-env")
+		cfg := DatabaseConfig{PasswordFile: file}
+		require.NoError(t, cfg.validate())
+		assert.Equal(t, "from-file", cfg.Password)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, cfg := range map[string]DatabaseConfig{
+			"driver":            {Driver: "mysql"},
+			"sslmode":           {SSLMode: "sometimes"},
+			"port":              {Port: 70000},
+			"statement timeout": {StatementTimeout: -time.Second},
+			"password file":     {PasswordFile: filepath.Join(t.TempDir(), "missing")},
+		} {
+			assert.Error(t, cfg.validate(), name)
+		}
+		t.Setenv("DB_PORT", "five")
+		assert.Error(t, (&DatabaseConfig{}).validate())
+	})
+}
+
+func TestDatabaseConfig_DSN(t *testing.T) {
+	cfg := DatabaseConfig{
+		Host:             "db",
+		Port:             5432,
+		User:             "postgres",
+		Password:         `it's a \ secret`,
+		Name:             "news",
+		SSLMode:          "disable",
+		StatementTimeout: 5 * time.Second,
+		ConnectTimeout:   10 * time.Second,
+	}
+	parsed, err := pgconn.ParseConfig(cfg.DSN())
+	require.NoError(t, err)
+	assert.Equal(t, "db", parsed.Host)
+	assert.Equal(t, uint16(5432), parsed.Port)
+	assert.Equal(t, "postgres", parsed.User)
+	assert.Equal(t, `it's a \ secret`, parsed.Password)
+	assert.Equal(t, "news", parsed.Database)
+	assert.Nil(t, parsed.TLSConfig)
+	assert.Equal(t, 10*time.Second, parsed.ConnectTimeout)
+	assert.Equal(t, "5000", parsed.RuntimeParams["statement_timeout"])
+}
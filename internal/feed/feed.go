@@ -1,66 +1,368 @@
 package feed
 
 import (
+	"context"
 	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/mmcdole/gofeed"
 
+	"github.com/moynur/news-app/internal/canonical"
+	"github.com/moynur/news-app/internal/config"
+	"github.com/moynur/news-app/internal/similarity"
 	"github.com/moynur/news-app/internal/store"
 )
 
 // This could all potentially be another service with a separate database
 type feeder struct {
-	store      store.Storer
-	rssFeedURL string
+	store       store.Storer
+	client      *http.Client
+	userAgent   string
+	retryBase   time.Duration
+	retryMax    time.Duration
+	maxFailures int
+	// fetchTimeout is how long a feed's fetch can take, and how long all the pages read for it can take together
+	fetchTimeout   time.Duration
+	maxPageFetches int
+	// jitter returns a number in [0,1) to spread out retries, it is swapped out in tests
+	jitter         func() float64
+	reloadInterval time.Duration
+	// stop is closed by Shutdown so no more polls are started, running tracks the feeds still being polled
+	stop     chan struct{}
+	stopOnce sync.Once
+	running  sync.WaitGroup
 }
 
-func NewFeeder(db store.Storer, rssFeedUrl string) *feeder {
+func NewFeeder(db store.Storer, cfg config.FeederConfig) *feeder {
+	random := rand.New(rand.NewSource(time.Now().UnixNano()))
+	var randomMu sync.Mutex
 	return &feeder{
-		store:      db,
-		rssFeedURL: rssFeedUrl,
+		store:          db,
+		client:         &http.Client{Timeout: cfg.FetchTimeout},
+		userAgent:      cfg.UserAgent,
+		retryBase:      cfg.RetryBaseDelay,
+		retryMax:       cfg.RetryMaxDelay,
+		maxFailures:    cfg.MaxConsecutiveFailures,
+		fetchTimeout:   cfg.FetchTimeout,
+		maxPageFetches: cfg.MaxPageFetches,
+		jitter: func() float64 {
+			randomMu.Lock()
+			defer randomMu.Unlock()
+			return random.Float64()
+		},
+		reloadInterval: cfg.ReloadInterval,
+		stop:           make(chan struct{}),
 	}
 }
 
 func isNotValid(article *gofeed.Item) bool {
-	return article.GUID == "" || article.Title == ""
+	return articleLink(article) == "" || article.Title == ""
 }
 
-func (s *feeder) RefreshArticles(rate int) {
-	ticker := time.NewTicker(time.Second * time.Duration(rate)).C
+// RefreshArticles polls every enabled feed straight away and then on its own interval, feeds which are added, changed
+// or removed are picked up every reload interval. It blocks until Shutdown is called or ctx is cancelled, cancelling
+// ctx abandons any poll in progress whereas Shutdown lets them finish
+func (s *feeder) RefreshArticles(ctx context.Context) {
+	s.running.Add(1)
+	defer s.running.Done()
+	polling := map[string]*polledFeed{}
+	defer func() {
+		for _, polled := range polling {
+			close(polled.stop)
+		}
+	}()
+
+	ticker := time.NewTicker(s.reloadInterval)
+	defer ticker.Stop()
 	for {
+		s.reload(ctx, polling)
 		select {
-		case <-ticker:
-			log.Println("ticking!")
-			s.LoadAndStoreArticles()
+		case <-ctx.Done():
+			return
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *feeder) refreshFeed(ctx context.Context, source config.FeedConfig, stop <-chan struct{}) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stop:
+			return
+		case <-stop:
+			return
+		case <-timer.C:
+			log.Println("ticking!", source.Name)
+			next := s.safeLoadAndStoreFeed(ctx, source)
+			timer.Reset(time.Until(next))
+		}
+	}
+}
+
+// Shutdown stops any more polls from starting and waits for the ones in progress to finish. If ctx expires first
+// its error is returned, the context given to RefreshArticles can then be cancelled to abandon them
+func (s *feeder) Shutdown(ctx context.Context) error {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// LoadAndStoreArticles does a single pass over every enabled feed
+func (s *feeder) LoadAndStoreArticles(ctx context.Context) {
+	sources, err := s.loadSources(ctx)
+	if err != nil {
+		log.Println("unable to load feeds", err)
+		return
+	}
+	for _, source := range sources {
+		if !source.Enabled {
+			continue
 		}
+		s.safeLoadAndStoreFeed(ctx, source)
 	}
 }
 
-func (s *feeder) LoadAndStoreArticles() {
-	fp := gofeed.NewParser()
-	feed, err := fp.ParseURL(s.rssFeedURL)
+// loadAndStoreFeed fetches the feed if it has changed and stores any new articles, it returns when the feed should
+// next be fetched
+func (s *feeder) loadAndStoreFeed(ctx context.Context, source config.FeedConfig) time.Time {
+	now := time.Now()
+	state, err := s.store.GetFeedState(ctx, source.Name)
 	if err != nil {
-		log.Printf("unable to load articles %e", err)
+		log.Println("unable to load feed state, fetching without it", source.Name, err)
+		state = store.FeedState{Name: source.Name}
 	}
+	if state.Paused {
+		log.Println("feed is paused after failing too many times", source.Name, state.LastError)
+		return now.Add(source.RefreshInterval)
+	}
+	if state.NextFetchAt != nil && now.Before(*state.NextFetchAt) {
+		log.Println("publisher asked not to fetch yet", source.Name, state.NextFetchAt)
+		return *state.NextFetchAt
+	}
+
+	result, err := s.fetch(ctx, source, &state)
+	if err != nil {
+		if ctx.Err() != nil {
+			// being shut down isn't the publisher's fault
+			return now
+		}
+		return s.recordFailure(ctx, state, now, err)
+	}
+	next := nextFetch(now, source.RefreshInterval, result.maxAge, state)
+	previousSuccess := state.LastSuccessAt
+	state.LastFetchedAt = &now
+	state.NextFetchAt = &next
+	state.LastSuccessAt = &now
+	state.ConsecutiveFailures = 0
+	state.LastError = ""
+	state.LastItemsSeen = 0
+	state.LastItemsInserted = 0
+	state.LastItemsUpdated = 0
+	state.LastDuplicatesSkipped = 0
+	if result.notModified {
+		log.Println("feed not modified", source.Name)
+	} else {
+		s.storeArticles(ctx, source, result.feed, &state, previousSuccess)
+	}
+	err = s.store.SaveFeedState(ctx, state)
+	if err != nil {
+		log.Println("unable to save feed state", source.Name, err)
+	}
+	return next
+}
+
+// storeArticles stores every valid item in the feed, counting what happened to them against the feed's state. Articles
+// published since the feed was last fetched successfully are new so when the feed gives neither an image nor a
+// canonical link their page is read for them, up to maxPageFetches pages within one fetchTimeout. An empty thumbnail
+// leaves an existing article's image as it is and gives a new one its feed's fallback image
+func (s *feeder) storeArticles(ctx context.Context, source config.FeedConfig, feed *gofeed.Feed, state *store.FeedState, since *time.Time) {
+	provider := feed.Title
+	if provider == "" {
+		provider = source.Name
+	}
+	pageCtx, cancel := context.WithTimeout(ctx, s.fetchTimeout)
+	defer cancel()
+	pagesFetched := 0
+	var articles []store.NewsArticle
 	for _, article := range feed.Items {
+		if ctx.Err() != nil {
+			log.Println("stopped storing articles", source.Name, ctx.Err())
+			return
+		}
 		if isNotValid(article) {
 			continue
 		}
-		if article.Image == nil || article.Image.URL == "" {
-			article.Image = &gofeed.Image{
-				URL: "https://pbs.twimg.com/profile_images/1140654461603287040/bUUAgDF6_400x400.jpg",
+		state.LastItemsSeen++
+		feedLink := canonical.URL(articleLink(article))
+		link := feedLink
+		image := thumbnail(article)
+		origin := feedCanonical(article)
+		if origin != "" {
+			link = canonical.URL(origin)
+		}
+		if image == "" && origin == "" && publishedSince(article, since) && pagesFetched < s.maxPageFetches && pageCtx.Err() == nil {
+			pagesFetched++
+			page, err := s.fetchPage(pageCtx, feedLink)
+			if err != nil {
+				log.Println("unable to read the article's page", feedLink, err)
+			}
+			if page.canonical != "" {
+				link = canonical.URL(page.canonical)
+			}
+			if image == "" {
+				image = page.image
 			}
 		}
-		err := s.store.CreateArticleIfNotExists(store.NewsArticle{
-			Title:       article.Title,
-			Description: article.Description,
-			Link:        article.GUID,
-			Thumbnail:   article.Image.URL,
-			CreatedAt:   time.Now(),
+		now := time.Now()
+		articles = append(articles, store.NewsArticle{
+			Title:           article.Title,
+			Description:     article.Description,
+			Content:         article.Content,
+			Link:            link,
+			LinkKey:         canonical.Key(link),
+			GUID:            article.GUID,
+			Authors:         authors(article),
+			Enclosures:      enclosures(article),
+			Categories:      categories(article, source),
+			Source:          source.Name,
+			Sources:         []store.ArticleSource{{Source: source.Name, LinkKey: canonical.Key(feedLink)}},
+			Provider:        provider,
+			Thumbnail:       image,
+			PublishedAt:     publishedAt(article, now),
+			SourceUpdatedAt: article.UpdatedParsed,
+			CreatedAt:       now,
+			Fingerprint:     fingerprint(article),
 		})
-		if err != nil {
-			log.Println("error creating an article", err)
+	}
+	if len(articles) == 0 {
+		return
+	}
+	result, err := s.store.UpsertArticles(ctx, articles)
+	if err != nil {
+		log.Println("error storing articles", source.Name, err)
+		return
+	}
+	state.LastItemsInserted += result.Inserted
+	state.LastItemsUpdated += result.Updated
+	state.LastDuplicatesSkipped += result.Unchanged
+}
+
+// articleLink is the item's own link, falling back to the guid when it's a url as some RSS feeds leave the link out.
+// Atom and JSON Feed ids are often urns which can't be opened so they're never used as the link
+func articleLink(article *gofeed.Item) string {
+	if article.Link != "" {
+		return article.Link
+	}
+	guid, err := url.Parse(article.GUID)
+	if err == nil && (guid.Scheme == "http" || guid.Scheme == "https") && guid.Host != "" {
+		return article.GUID
+	}
+	return ""
+}
+
+func authors(article *gofeed.Item) store.StringList {
+	people := article.Authors
+	if len(people) == 0 && article.Author != nil {
+		people = []*gofeed.Person{article.Author}
+	}
+	var names store.StringList
+	for _, person := range people {
+		if person == nil {
+			continue
+		}
+		name := strings.TrimSpace(person.Name)
+		if name == "" {
+			name = strings.TrimSpace(person.Email)
+		}
+		if name != "" {
+			names = append(names, name)
 		}
 	}
+	return names
+}
+
+func enclosures(article *gofeed.Item) store.Enclosures {
+	var attached store.Enclosures
+	for _, enclosure := range article.Enclosures {
+		if enclosure == nil || enclosure.URL == "" {
+			continue
+		}
+		// a missing or made up length is common so it's left as unknown
+		length, _ := strconv.ParseInt(strings.TrimSpace(enclosure.Length), 10, 64)
+		attached = append(attached, store.Enclosure{
+			URL:    enclosure.URL,
+			Type:   enclosure.Type,
+			Length: length,
+		})
+	}
+	return attached
+}
+
+// publishedAt is when the publisher says the item was published, or last updated if that's all it gives. Articles
+// without either are given the time they were ingested, as are ones dated in the future so they can't stay at the top
+func publishedAt(article *gofeed.Item, ingested time.Time) time.Time {
+	published := article.PublishedParsed
+	if published == nil {
+		published = article.UpdatedParsed
+	}
+	if published == nil || published.After(ingested) {
+		return ingested
+	}
+	return *published
+}
+
+// categories uses the categories the publisher gave the item, falling back to the feed's default category
+func categories(article *gofeed.Item, source config.FeedConfig) []store.Category {
+	var categories []store.Category
+	for _, name := range article.Categories {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		categories = append(categories, store.Category{Name: name})
+	}
+	if len(categories) == 0 && source.DefaultCategory != "" {
+		categories = append(categories, store.Category{Name: source.DefaultCategory})
+	}
+	return categories
+}
+
+// fingerprint is used to find other articles about the same story
+func fingerprint(article *gofeed.Item) int64 {
+	return int64(similarity.Fingerprint(article.Title, plainText(article.Description)))
+}
+
+// plainText strips any html from the text so markup doesn't make articles look alike
+func plainText(text string) string {
+	if !strings.Contains(text, "<") {
+		return text
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(text))
+	if err != nil {
+		return text
+	}
+	return doc.Text()
 }
@@ -1,3 +1,556 @@
 package feed
 
-// needs tests
\ No newline at end of file
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mmcdole/gofeed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/moynur/news-app/internal/config"
+	"github.com/moynur/news-app/internal/store"
+)
+
+const testRSS = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/">
+<channel>
+	<title>Sky News</title>
+	<ttl>15</ttl>
+	<skipHours><hour>3</hour></skipHours>
+	<item>
+		<title>Some headline</title>
+		<description>Some summary</description>
+		<guid>https://news.sky.com/story/1</guid>
+		<category>Politics</category>
+		<media:thumbnail url="https://e3.365dm.com/1.jpg" width="70" height="70"/>
+		<media:content url="https://e3.365dm.com/1-large.jpg" type="image/jpeg" width="1600" height="900"/>
+	</item>
+</channel>
+</rss>`
+
+func TestFeeder_LoadAndStoreArticles_ConditionalGet(t *testing.T) {
+	var requests []*http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2026 15:04:05 GMT")
+		_, _ = w.Write([]byte(testRSS))
+	}))
+	defer srv.Close()
+
+	ctrl := gomock.NewController(t)
+	ms := store.NewMockStorer(ctrl)
+	source := config.FeedConfig{Name: "sky", URL: srv.URL, RefreshInterval: time.Minute, Enabled: true}
+	f := NewFeeder(ms, config.FeederConfig{UserAgent: "test-agent", FetchTimeout: time.Second})
+
+	var saved store.FeedState
+	// the feed has been fetched before so the pages of its undated articles aren't read
+	lastSuccess := time.Now()
+	ms.EXPECT().GetFeedState(gomock.Any(), "sky").Return(store.FeedState{Name: "sky", LastSuccessAt: &lastSuccess}, nil)
+	ms.EXPECT().SaveFeedState(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, state store.FeedState) error {
+		saved = state
+		return nil
+	})
+	ms.EXPECT().UpsertArticles(gomock.Any(), gomock.Len(1)).DoAndReturn(func(_ context.Context, articles []store.NewsArticle) (store.UpsertResult, error) {
+		article := articles[0]
+		assert.Equal(t, "Some headline", article.Title)
+		assert.Equal(t, "sky", article.Source)
+		assert.Equal(t, "Sky News", article.Provider)
+		assert.Equal(t, []store.Category{{Name: "Politics"}}, article.Categories)
+		assert.Equal(t, "https://e3.365dm.com/1-large.jpg", article.Thumbnail)
+		return store.UpsertResult{Inserted: 1}, nil
+	})
+	expectFeeds(ms, source)
+	f.LoadAndStoreArticles(context.Background())
+
+	assert.Equal(t, `"v1"`, saved.ETag)
+	assert.Equal(t, "Mon, 02 Jan 2026 15:04:05 GMT", saved.LastModified)
+	assert.Equal(t, 15, saved.TTLMinutes)
+	assert.Equal(t, "3", saved.SkipHours)
+	assert.Equal(t, http.StatusOK, saved.LastStatus)
+	assert.Equal(t, 1, saved.LastItemsSeen)
+	assert.Equal(t, 1, saved.LastItemsInserted)
+
+	// once the feed has been seen the next fetch is conditional and nothing is stored when it hasn't changed
+	saved.NextFetchAt = nil
+	ms.EXPECT().GetFeedState(gomock.Any(), "sky").Return(saved, nil)
+	ms.EXPECT().SaveFeedState(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, state store.FeedState) error {
+		saved = state
+		return nil
+	})
+	expectFeeds(ms, source)
+	f.LoadAndStoreArticles(context.Background())
+	assert.Equal(t, http.StatusNotModified, saved.LastStatus)
+	assert.Equal(t, 0, saved.LastItemsInserted)
+
+	assert.Len(t, requests, 2)
+	assert.Equal(t, "test-agent", requests[1].Header.Get("User-Agent"))
+	assert.Equal(t, "Mon, 02 Jan 2026 15:04:05 GMT", requests[1].Header.Get("If-Modified-Since"))
+}
+
+const testAtom = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:media="http://search.yahoo.com/mrss/">
+	<title>Example Atom</title>
+	<entry>
+		<title>Atom headline</title>
+		<id>urn:uuid:1225c695-cfb8-4ebb-aaaa-80da344efa6a</id>
+		<link rel="alternate" href="https://example.com/atom/1"/>
+		<link rel="enclosure" type="audio/mpeg" length="1337" href="https://example.com/atom/1.mp3"/>
+		<published>2026-01-02T09:00:00Z</published>
+		<updated>2026-01-02T10:30:00Z</updated>
+		<author><name>Jane Smith</name></author>
+		<media:thumbnail url="https://example.com/atom/1.jpg"/>
+		<summary>Atom summary</summary>
+		<content type="html">&lt;p&gt;Full story&lt;/p&gt;</content>
+	</entry>
+</feed>`
+
+const testJSONFeed = `{
+	"version": "https://jsonfeed.org/version/1.1",
+	"title": "Example JSON",
+	"items": [
+		{
+			"id": "42",
+			"url": "https://example.com/json/42",
+			"title": "JSON headline",
+			"content_html": "<p>Full story</p>",
+			"summary": "JSON summary",
+			"image": "https://example.com/json/42.jpg",
+			"date_published": "2026-01-02T09:00:00Z",
+			"date_modified": "2026-01-02T10:30:00Z",
+			"authors": [{"name": "John Doe"}],
+			"attachments": [{"url": "https://example.com/json/42.mp4", "mime_type": "video/mp4", "size_in_bytes": 2048}]
+		},
+		{
+			"id": "no-url",
+			"title": "Has no link so can't be opened"
+		}
+	]
+}`
+
+func TestFeeder_LoadAndStoreArticles_Categories(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<rss version="2.0"><channel><title>Example</title>
+			<item><title>Categorised</title><link>https://example.com/1</link><category>UK</category><category> </category><category>Politics</category></item>
+			<item><title>Uncategorised</title><link>https://example.com/2</link></item>
+		</channel></rss>`))
+	}))
+	defer srv.Close()
+
+	ctrl := gomock.NewController(t)
+	ms := store.NewMockStorer(ctrl)
+	source := config.FeedConfig{Name: "example", URL: srv.URL, RefreshInterval: time.Minute, DefaultCategory: "news", Enabled: true}
+	f := NewFeeder(ms, config.FeederConfig{FetchTimeout: time.Second})
+
+	var saved []store.NewsArticle
+	lastSuccess := time.Now()
+	ms.EXPECT().GetFeedState(gomock.Any(), "example").Return(store.FeedState{Name: "example", LastSuccessAt: &lastSuccess}, nil)
+	ms.EXPECT().UpsertArticles(gomock.Any(), gomock.Len(2)).DoAndReturn(func(_ context.Context, articles []store.NewsArticle) (store.UpsertResult, error) {
+		saved = articles
+		return store.UpsertResult{Inserted: 2}, nil
+	})
+	ms.EXPECT().SaveFeedState(gomock.Any(), gomock.Any()).Return(nil)
+	f.loadAndStoreFeed(context.Background(), source)
+
+	require.Len(t, saved, 2)
+	assert.Equal(t, []store.Category{{Name: "UK"}, {Name: "Politics"}}, saved[0].Categories, "the item's own categories are used over the default")
+	assert.Equal(t, []store.Category{{Name: "news"}}, saved[1].Categories, "an item without categories gets the feed's default")
+}
+
+func TestFeeder_LoadAndStoreArticles_PublisherFields(t *testing.T) {
+	published := time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC)
+	updated := time.Date(2026, 1, 2, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		body string
+		want store.NewsArticle
+	}{
+		{
+			name: "atom",
+			body: testAtom,
+			want: store.NewsArticle{
+				Title:           "Atom headline",
+				Description:     "Atom summary",
+				Content:         "<p>Full story</p>",
+				Link:            "https://example.com/atom/1",
+				GUID:            "urn:uuid:1225c695-cfb8-4ebb-aaaa-80da344efa6a",
+				Authors:         store.StringList{"Jane Smith"},
+				Enclosures:      store.Enclosures{{URL: "https://example.com/atom/1.mp3", Type: "audio/mpeg", Length: 1337}},
+				PublishedAt:     published,
+				SourceUpdatedAt: &updated,
+			},
+		},
+		{
+			name: "json feed",
+			body: testJSONFeed,
+			want: store.NewsArticle{
+				Title:           "JSON headline",
+				Description:     "JSON summary",
+				Content:         "<p>Full story</p>",
+				Link:            "https://example.com/json/42",
+				GUID:            "42",
+				Authors:         store.StringList{"John Doe"},
+				Enclosures:      store.Enclosures{{URL: "https://example.com/json/42.mp4", Type: "video/mp4", Length: 2048}},
+				PublishedAt:     published,
+				SourceUpdatedAt: &updated,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			ctrl := gomock.NewController(t)
+			ms := store.NewMockStorer(ctrl)
+			source := config.FeedConfig{Name: "example", URL: srv.URL, RefreshInterval: time.Minute, Enabled: true}
+			f := NewFeeder(ms, config.FeederConfig{FetchTimeout: time.Second})
+
+			lastSuccess := time.Now()
+			ms.EXPECT().GetFeedState(gomock.Any(), "example").Return(store.FeedState{Name: "example", LastSuccessAt: &lastSuccess}, nil)
+			ms.EXPECT().SaveFeedState(gomock.Any(), gomock.Any()).Return(nil)
+			ms.EXPECT().UpsertArticles(gomock.Any(), gomock.Len(1)).DoAndReturn(func(_ context.Context, articles []store.NewsArticle) (store.UpsertResult, error) {
+				article := articles[0]
+				assert.Equal(t, tt.want.Title, article.Title)
+				assert.Equal(t, tt.want.Description, article.Description)
+				assert.Equal(t, tt.want.Content, article.Content)
+				assert.Equal(t, tt.want.Link, article.Link)
+				assert.Equal(t, tt.want.GUID, article.GUID)
+				assert.Equal(t, tt.want.Authors, article.Authors)
+				assert.Equal(t, tt.want.Enclosures, article.Enclosures)
+				assert.True(t, tt.want.PublishedAt.Equal(article.PublishedAt))
+				assert.True(t, tt.want.SourceUpdatedAt.Equal(*article.SourceUpdatedAt))
+				assert.NotZero(t, article.Fingerprint)
+				return store.UpsertResult{Inserted: 1}, nil
+			})
+			f.loadAndStoreFeed(context.Background(), source)
+		})
+	}
+}
+
+func TestFeeder_LoadAndStoreArticles_Updated(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testRSS))
+	}))
+	defer srv.Close()
+
+	ctrl := gomock.NewController(t)
+	ms := store.NewMockStorer(ctrl)
+	source := config.FeedConfig{Name: "sky", URL: srv.URL, RefreshInterval: time.Minute, Enabled: true}
+	f := NewFeeder(ms, config.FeederConfig{FetchTimeout: time.Second})
+
+	var saved store.FeedState
+	lastSuccess := time.Now()
+	ms.EXPECT().GetFeedState(gomock.Any(), "sky").Return(store.FeedState{Name: "sky", LastSuccessAt: &lastSuccess, LastItemsUpdated: 4}, nil)
+	ms.EXPECT().UpsertArticles(gomock.Any(), gomock.Len(1)).Return(store.UpsertResult{Updated: 1}, nil)
+	ms.EXPECT().SaveFeedState(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, state store.FeedState) error {
+		saved = state
+		return nil
+	})
+	f.loadAndStoreFeed(context.Background(), source)
+	assert.Equal(t, 1, saved.LastItemsSeen)
+	assert.Equal(t, 0, saved.LastItemsInserted)
+	assert.Equal(t, 1, saved.LastItemsUpdated)
+	assert.Equal(t, 0, saved.LastDuplicatesSkipped)
+}
+
+func TestFeeder_LoadAndStoreArticles_UpsertFails(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testRSS))
+	}))
+	defer srv.Close()
+
+	ctrl := gomock.NewController(t)
+	ms := store.NewMockStorer(ctrl)
+	source := config.FeedConfig{Name: "sky", URL: srv.URL, RefreshInterval: time.Minute, Enabled: true}
+	f := NewFeeder(ms, config.FeederConfig{FetchTimeout: time.Second})
+
+	var saved store.FeedState
+	lastSuccess := time.Now()
+	ms.EXPECT().GetFeedState(gomock.Any(), "sky").Return(store.FeedState{Name: "sky", LastSuccessAt: &lastSuccess}, nil)
+	ms.EXPECT().UpsertArticles(gomock.Any(), gomock.Len(1)).Return(store.UpsertResult{}, errors.New("cant connect to db"))
+	ms.EXPECT().SaveFeedState(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, state store.FeedState) error {
+		saved = state
+		return nil
+	})
+	f.loadAndStoreFeed(context.Background(), source)
+	assert.Equal(t, 1, saved.LastItemsSeen)
+	assert.Equal(t, 0, saved.LastItemsInserted)
+	assert.Equal(t, 0, saved.LastItemsUpdated)
+	assert.Equal(t, 0, saved.LastDuplicatesSkipped)
+}
+
+func Test_articleLink(t *testing.T) {
+	assert.Equal(t, "https://example.com/1", articleLink(&gofeed.Item{Link: "https://example.com/1", GUID: "1"}))
+	assert.Equal(t, "https://example.com/2", articleLink(&gofeed.Item{GUID: "https://example.com/2"}))
+	assert.Equal(t, "", articleLink(&gofeed.Item{GUID: "tag:example.com,2026:3"}))
+}
+
+func Test_fingerprint(t *testing.T) {
+	plain := &gofeed.Item{Title: "Storm warning issued", Description: "Heavy rain is expected across the north"}
+	html := &gofeed.Item{Title: "Storm warning issued", Description: "<p>Heavy rain is expected <b>across</b> the north</p>"}
+	assert.Equal(t, fingerprint(plain), fingerprint(html))
+}
+
+func Test_publishedAt(t *testing.T) {
+	ingested := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	published := ingested.Add(-2 * time.Hour)
+	updated := ingested.Add(-time.Hour)
+	future := ingested.Add(time.Hour)
+	tests := []struct {
+		name string
+		item *gofeed.Item
+		want time.Time
+	}{
+		{name: "published", item: &gofeed.Item{PublishedParsed: &published, UpdatedParsed: &updated}, want: published},
+		{name: "only updated", item: &gofeed.Item{UpdatedParsed: &updated}, want: updated},
+		{name: "neither", item: &gofeed.Item{}, want: ingested},
+		{name: "in the future", item: &gofeed.Item{PublishedParsed: &future}, want: ingested},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, publishedAt(tt.item, ingested))
+		})
+	}
+}
+
+func TestFeeder_LoadAndStoreArticles_NotBeforeNextFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("feed should not have been fetched")
+	}))
+	defer srv.Close()
+
+	ctrl := gomock.NewController(t)
+	ms := store.NewMockStorer(ctrl)
+	source := config.FeedConfig{Name: "sky", URL: srv.URL, RefreshInterval: time.Minute, Enabled: true}
+	f := NewFeeder(ms, config.FeederConfig{FetchTimeout: time.Second})
+
+	next := time.Now().Add(time.Hour)
+	ms.EXPECT().GetFeedState(gomock.Any(), "sky").Return(store.FeedState{Name: "sky", NextFetchAt: &next}, nil)
+	assert.Equal(t, next, f.loadAndStoreFeed(context.Background(), source))
+}
+
+func Test_nextFetch(t *testing.T) {
+	now := time.Date(2026, 1, 5, 1, 30, 0, 0, time.UTC) // a monday
+	tests := []struct {
+		name   string
+		maxAge time.Duration
+		state  store.FeedState
+		want   time.Time
+	}{
+		{
+			name: "uses the refresh interval without hints",
+			want: now.Add(time.Minute),
+		},
+		{
+			name:   "honours a longer cache max age",
+			maxAge: 10 * time.Minute,
+			want:   now.Add(10 * time.Minute),
+		},
+		{
+			name:  "honours the rss ttl",
+			state: store.FeedState{TTLMinutes: 30},
+			want:  now.Add(30 * time.Minute),
+		},
+		{
+			name:  "moves past skipped hours",
+			state: store.FeedState{SkipHours: "1,2"},
+			want:  time.Date(2026, 1, 5, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "moves past skipped days",
+			state: store.FeedState{SkipDays: "Monday"},
+			want:  time.Date(2026, 1, 6, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, nextFetch(now, time.Minute, tt.maxAge, tt.state))
+		})
+	}
+}
+
+func Test_maxAge(t *testing.T) {
+	assert.Equal(t, 5*time.Minute, maxAge("public, max-age=300"))
+	assert.Equal(t, time.Duration(0), maxAge("no-cache"))
+	assert.Equal(t, time.Duration(0), maxAge("max-age=abc"))
+}
+
+func TestFeeder_Shutdown(t *testing.T) {
+	fetching := make(chan struct{})
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(fetching)
+		<-release
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer srv.Close()
+
+	ctrl := gomock.NewController(t)
+	ms := store.NewMockStorer(ctrl)
+	source := config.FeedConfig{Name: "sky", URL: srv.URL, RefreshInterval: time.Minute, Enabled: true}
+	f := NewFeeder(ms, config.FeederConfig{FetchTimeout: time.Second, ReloadInterval: time.Hour})
+
+	ms.EXPECT().GetFeedState(gomock.Any(), "sky").Return(store.FeedState{Name: "sky"}, nil)
+	// the poll in progress is allowed to finish and save its state
+	ms.EXPECT().SaveFeedState(gomock.Any(), gomock.Any()).Return(nil)
+
+	expectFeeds(ms, source)
+	refreshDone := make(chan struct{})
+	go func() {
+		f.RefreshArticles(context.Background())
+		close(refreshDone)
+	}()
+	<-fetching
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, f.Shutdown(shutdownCtx), context.DeadlineExceeded)
+
+	close(release)
+	assert.NoError(t, f.Shutdown(context.Background()))
+	<-refreshDone
+}
+
+func TestFeeder_LoadAndStoreArticles_Failures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "7200")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ctrl := gomock.NewController(t)
+	ms := store.NewMockStorer(ctrl)
+	source := config.FeedConfig{Name: "sky", URL: srv.URL, RefreshInterval: time.Minute, Enabled: true}
+	f := NewFeeder(ms, config.FeederConfig{
+		FetchTimeout:           time.Second,
+		RetryBaseDelay:         time.Minute,
+		RetryMaxDelay:          time.Hour,
+		MaxConsecutiveFailures: 3,
+	})
+
+	var saved store.FeedState
+	// the counts are from the last run which worked
+	ms.EXPECT().GetFeedState(gomock.Any(), "sky").Return(store.FeedState{
+		Name:                  "sky",
+		ConsecutiveFailures:   1,
+		LastItemsSeen:         5,
+		LastItemsInserted:     2,
+		LastItemsUpdated:      1,
+		LastDuplicatesSkipped: 2,
+	}, nil)
+	ms.EXPECT().SaveFeedState(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, state store.FeedState) error {
+		saved = state
+		return nil
+	})
+	next := f.loadAndStoreFeed(context.Background(), source)
+
+	assert.Equal(t, 2, saved.ConsecutiveFailures)
+	assert.Equal(t, "unexpected status fetching feed 503", saved.LastError)
+	assert.False(t, saved.Paused)
+	assert.Equal(t, 0, saved.LastItemsSeen)
+	assert.Equal(t, 0, saved.LastItemsInserted)
+	assert.Equal(t, 0, saved.LastItemsUpdated)
+	assert.Equal(t, 0, saved.LastDuplicatesSkipped)
+	// the publisher asked for longer than the backoff
+	assert.WithinDuration(t, time.Now().Add(2*time.Hour), next, time.Minute)
+
+	ms.EXPECT().GetFeedState(gomock.Any(), "sky").Return(store.FeedState{Name: "sky", ConsecutiveFailures: 2}, nil)
+	ms.EXPECT().SaveFeedState(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, state store.FeedState) error {
+		saved = state
+		return nil
+	})
+	f.loadAndStoreFeed(context.Background(), source)
+	assert.Equal(t, 3, saved.ConsecutiveFailures)
+	assert.True(t, saved.Paused)
+}
+
+func TestFeeder_LoadAndStoreArticles_Paused(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("paused feed should not have been fetched")
+	}))
+	defer srv.Close()
+
+	ctrl := gomock.NewController(t)
+	ms := store.NewMockStorer(ctrl)
+	source := config.FeedConfig{Name: "sky", URL: srv.URL, RefreshInterval: time.Minute, Enabled: true}
+	f := NewFeeder(ms, config.FeederConfig{FetchTimeout: time.Second})
+
+	ms.EXPECT().GetFeedState(gomock.Any(), "sky").Return(store.FeedState{Name: "sky", Paused: true}, nil)
+	expectFeeds(ms, source)
+	f.LoadAndStoreArticles(context.Background())
+}
+
+func TestFeeder_backoff(t *testing.T) {
+	f := NewFeeder(nil, config.FeederConfig{RetryBaseDelay: time.Minute, RetryMaxDelay: 10 * time.Minute})
+	f.jitter = func() float64 { return 0.999999 }
+
+	assert.InDelta(t, float64(time.Minute), float64(f.backoff(1)), float64(time.Millisecond))
+	assert.InDelta(t, float64(4*time.Minute), float64(f.backoff(3)), float64(time.Millisecond))
+	assert.InDelta(t, float64(10*time.Minute), float64(f.backoff(20)), float64(time.Millisecond))
+
+	f.jitter = func() float64 { return 0 }
+	assert.Equal(t, 2*time.Minute, f.backoff(3))
+}
+
+func expectFeeds(ms *store.MockStorer, sources ...config.FeedConfig) {
+	var feeds []store.Feed
+	for _, source := range sources {
+		feeds = append(feeds, store.Feed{
+			Name:                   source.Name,
+			URL:                    source.URL,
+			RefreshIntervalSeconds: int(source.RefreshInterval / time.Second),
+			DefaultCategory:        source.DefaultCategory,
+			Enabled:                source.Enabled,
+		})
+	}
+	ms.EXPECT().GetFeeds(gomock.Any()).Return(feeds, nil)
+}
+
+func TestFeeder_reload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ms := store.NewMockStorer(ctrl)
+	f := NewFeeder(ms, config.FeederConfig{FetchTimeout: time.Second})
+	// the feeds being polled are never due so nothing is fetched
+	next := time.Now().Add(time.Hour)
+	ms.EXPECT().GetFeedState(gomock.Any(), gomock.Any()).Return(store.FeedState{NextFetchAt: &next}, nil).AnyTimes()
+
+	uk := config.FeedConfig{Name: "sky-news-uk", URL: "http://sky/uk.xml", RefreshInterval: time.Minute, Enabled: true}
+	world := config.FeedConfig{Name: "sky-news-world", URL: "http://sky/world.xml", RefreshInterval: time.Minute}
+	polling := map[string]*polledFeed{}
+
+	expectFeeds(ms, uk, world)
+	f.reload(context.Background(), polling)
+	assert.Len(t, polling, 1)
+	assert.Equal(t, uk, polling["sky-news-uk"].source)
+	ukStop := polling["sky-news-uk"].stop
+
+	// enabling a feed starts it and changing one restarts it
+	world.Enabled = true
+	uk.RefreshInterval = 5 * time.Minute
+	expectFeeds(ms, uk, world)
+	f.reload(context.Background(), polling)
+	assert.Len(t, polling, 2)
+	assert.Equal(t, uk, polling["sky-news-uk"].source)
+	assert.Equal(t, world, polling["sky-news-world"].source)
+	_, open := <-ukStop
+	assert.False(t, open)
+
+	// removing every feed stops them all
+	expectFeeds(ms)
+	f.reload(context.Background(), polling)
+	assert.Empty(t, polling)
+	assert.NoError(t, f.Shutdown(context.Background()))
+}
@@ -0,0 +1,216 @@
+package feed
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/mmcdole/gofeed/json"
+	"github.com/mmcdole/gofeed/rss"
+
+	"github.com/moynur/news-app/internal/config"
+	"github.com/moynur/news-app/internal/store"
+)
+
+// maxFeedSize stops a misbehaving publisher from making us read an endless body
+const maxFeedSize = 10 << 20
+
+type fetchResult struct {
+	feed        *gofeed.Feed
+	notModified bool
+	// maxAge is how long the publisher has said the response can be cached for
+	maxAge time.Duration
+}
+
+// fetch does a conditional GET for the feed, the state is updated with the validators and hints the publisher sent
+// so the next fetch can be conditional too
+func (s *feeder) fetch(ctx context.Context, source config.FeedConfig, state *store.FeedState) (fetchResult, error) {
+	var result fetchResult
+	state.LastStatus = 0
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source.URL, nil)
+	if err != nil {
+		return result, fmt.Errorf("unable to create request, %w", err)
+	}
+	req.Header.Set("User-Agent", s.userAgent)
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/feed+json, application/xml;q=0.9, */*;q=0.8")
+	if state.ETag != "" {
+		req.Header.Set("If-None-Match", state.ETag)
+	}
+	if state.LastModified != "" {
+		req.Header.Set("If-Modified-Since", state.LastModified)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return result, fmt.Errorf("unable to fetch feed, %w", err)
+	}
+	defer resp.Body.Close()
+
+	state.LastStatus = resp.StatusCode
+	result.maxAge = maxAge(resp.Header.Get("Cache-Control"))
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		result.notModified = true
+		return result, nil
+	case http.StatusOK:
+	default:
+		return result, &statusError{
+			code:       resp.StatusCode,
+			retryAfter: retryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxFeedSize))
+	if err != nil {
+		return result, fmt.Errorf("unable to read feed, %w", err)
+	}
+	result.feed, err = parse(body, state)
+	if err != nil {
+		return result, err
+	}
+	// validators are only kept once the body has been parsed, otherwise a bad copy would be cached forever
+	state.ETag = resp.Header.Get("ETag")
+	state.LastModified = resp.Header.Get("Last-Modified")
+	return result, nil
+}
+
+// parse reads any supported feed type, RSS feeds are parsed directly so their ttl and skip hints can be kept
+func parse(body []byte, state *store.FeedState) (*gofeed.Feed, error) {
+	switch gofeed.DetectFeedType(bytes.NewReader(body)) {
+	case gofeed.FeedTypeRSS:
+		return parseRSS(body, state)
+	case gofeed.FeedTypeJSON:
+		return parseJSON(body)
+	}
+	feed, err := gofeed.NewParser().Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse feed, %w", err)
+	}
+	return feed, nil
+}
+
+// parseRSS keeps the hints RSS gives about when to fetch the feed again which gofeed otherwise drops
+func parseRSS(body []byte, state *store.FeedState) (*gofeed.Feed, error) {
+	rssFeed, err := (&rss.Parser{}).Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse rss feed, %w", err)
+	}
+	state.TTLMinutes, _ = strconv.Atoi(strings.TrimSpace(rssFeed.TTL))
+	state.SkipHours = strings.Join(rssFeed.SkipHours, ",")
+	state.SkipDays = strings.Join(rssFeed.SkipDays, ",")
+	feed, err := (&gofeed.DefaultRSSTranslator{}).Translate(rssFeed)
+	if err != nil {
+		return nil, fmt.Errorf("unable to translate rss feed, %w", err)
+	}
+	return feed, nil
+}
+
+// parseJSON fixes the length of attachments as gofeed fills it with their duration rather than their size
+func parseJSON(body []byte) (*gofeed.Feed, error) {
+	jsonFeed, err := (&json.Parser{}).Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse json feed, %w", err)
+	}
+	feed, err := (&gofeed.DefaultJSONTranslator{}).Translate(jsonFeed)
+	if err != nil {
+		return nil, fmt.Errorf("unable to translate json feed, %w", err)
+	}
+	for i, item := range jsonFeed.Items {
+		if item.Attachments == nil || i >= len(feed.Items) {
+			continue
+		}
+		for j, attachment := range *item.Attachments {
+			if j < len(feed.Items[i].Enclosures) {
+				feed.Items[i].Enclosures[j].Length = strconv.FormatInt(attachment.SizeInBytes, 10)
+			}
+		}
+	}
+	return feed, nil
+}
+
+// statusError is returned when the publisher responds with anything other than the feed or not modified
+type statusError struct {
+	code int
+	// retryAfter is how long the publisher asked us to wait, usually sent with a 429 or 503
+	retryAfter time.Duration
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status fetching feed %d", e.code)
+}
+
+// retryAfter reads a Retry-After header which can either be a number of seconds or a date
+func retryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+// maxAge reads the max-age directive from a Cache-Control header, no-cache or no-store mean no hint at all
+func maxAge(cacheControl string) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.TrimSpace(strings.ToLower(directive))
+		if !strings.HasPrefix(directive, "max-age=") {
+			continue
+		}
+		seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+		if err != nil || seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	return 0
+}
+
+// nextFetch works out when the feed should be polled again, never sooner than the interval or the publisher's hints
+// allow and never in an hour or day the publisher has asked to be skipped
+func nextFetch(now time.Time, interval time.Duration, maxAge time.Duration, state store.FeedState) time.Time {
+	wait := interval
+	if maxAge > wait {
+		wait = maxAge
+	}
+	if ttl := time.Duration(state.TTLMinutes) * time.Minute; ttl > wait {
+		wait = ttl
+	}
+	next := now.Add(wait)
+
+	skipHours := map[int]bool{}
+	for _, hour := range strings.Split(state.SkipHours, ",") {
+		if h, err := strconv.Atoi(strings.TrimSpace(hour)); err == nil {
+			// RSS allows 24 to mean midnight
+			skipHours[h%24] = true
+		}
+	}
+	skipDays := map[string]bool{}
+	for _, day := range strings.Split(state.SkipDays, ",") {
+		if day = strings.TrimSpace(strings.ToLower(day)); day != "" {
+			skipDays[day] = true
+		}
+	}
+	if len(skipHours) == 0 && len(skipDays) == 0 {
+		return next
+	}
+	// skip hints are in GMT, a week of hours is enough to find a slot unless every hour is skipped
+	for i := 0; i < 7*24; i++ {
+		utc := next.UTC()
+		if !skipHours[utc.Hour()] && !skipDays[strings.ToLower(utc.Weekday().String())] {
+			return next
+		}
+		next = utc.Truncate(time.Hour).Add(time.Hour)
+	}
+	return now.Add(wait)
+}
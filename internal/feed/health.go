@@ -0,0 +1,73 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/moynur/news-app/internal/config"
+	"github.com/moynur/news-app/internal/store"
+)
+
+// safeLoadAndStoreFeed stops a feed which panics while being parsed or stored from taking down every other feed, the
+// panic is recorded as a failure like any other
+func (s *feeder) safeLoadAndStoreFeed(ctx context.Context, source config.FeedConfig) (next time.Time) {
+	defer func() {
+		if r := recover(); r != nil {
+			now := time.Now()
+			state, err := s.store.GetFeedState(ctx, source.Name)
+			if err != nil {
+				log.Println("unable to load feed state after panic", source.Name, err)
+				state = store.FeedState{Name: source.Name}
+			}
+			next = s.recordFailure(ctx, state, now, fmt.Errorf("panic polling feed: %v", r))
+		}
+	}()
+	return s.loadAndStoreFeed(ctx, source)
+}
+
+// recordFailure saves the failure against the feed and works out when to retry, backing off exponentially the more
+// times in a row it fails and pausing it once it has failed too many times
+func (s *feeder) recordFailure(ctx context.Context, state store.FeedState, now time.Time, fetchErr error) time.Time {
+	log.Printf("unable to load articles from %s %v", state.Name, fetchErr)
+	state.ConsecutiveFailures++
+	state.LastError = fetchErr.Error()
+	state.LastFetchedAt = &now
+	state.LastItemsSeen = 0
+	state.LastItemsInserted = 0
+	state.LastItemsUpdated = 0
+	state.LastDuplicatesSkipped = 0
+
+	wait := s.backoff(state.ConsecutiveFailures)
+	var statusErr *statusError
+	if errors.As(fetchErr, &statusErr) && statusErr.retryAfter > wait {
+		wait = statusErr.retryAfter
+	}
+	next := now.Add(wait)
+	state.NextFetchAt = &next
+
+	if s.maxFailures > 0 && state.ConsecutiveFailures >= s.maxFailures {
+		log.Println("pausing feed after too many failures", state.Name, state.ConsecutiveFailures)
+		state.Paused = true
+	}
+	err := s.store.SaveFeedState(ctx, state)
+	if err != nil {
+		log.Println("unable to save feed state", state.Name, err)
+	}
+	return next
+}
+
+// backoff doubles the retry delay for every failure in a row up to the max, the delay is then jittered between half
+// and all of it so feeds which failed at the same time don't all retry at the same time
+func (s *feeder) backoff(failures int) time.Duration {
+	wait := s.retryBase
+	for i := 1; i < failures && wait < s.retryMax; i++ {
+		wait *= 2
+	}
+	if wait > s.retryMax {
+		wait = s.retryMax
+	}
+	return wait/2 + time.Duration(s.jitter()*float64(wait/2))
+}
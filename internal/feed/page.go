@@ -0,0 +1,85 @@
+package feed
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mmcdole/gofeed"
+
+	"github.com/moynur/news-app/internal/canonical"
+)
+
+// maxPageSize is plenty to reach the head of any article page
+const maxPageSize = 2 << 20
+
+// articlePage is what the article's own page says about it
+type articlePage struct {
+	// canonical is the link the publisher says the article should be known by
+	canonical string
+	// image is the og:image, used when the feed has no image for the article
+	image string
+}
+
+// fetchPage reads the head of the article's page. It means another request to the publisher so it's only done for
+// articles which haven't been seen before and the feed doesn't say enough about
+func (s *feeder) fetchPage(ctx context.Context, link string) (articlePage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return articlePage{}, fmt.Errorf("unable to create request, %w", err)
+	}
+	req.Header.Set("User-Agent", s.userAgent)
+	req.Header.Set("Accept", "text/html")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return articlePage{}, fmt.Errorf("unable to fetch page, %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return articlePage{}, fmt.Errorf("unexpected status fetching page %d", resp.StatusCode)
+	}
+	doc, err := goquery.NewDocumentFromReader(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return articlePage{}, fmt.Errorf("unable to parse page, %w", err)
+	}
+	var page articlePage
+	if href := strings.TrimSpace(doc.Find(`link[rel="canonical"]`).First().AttrOr("href", "")); href != "" {
+		page.canonical = resolve(link, href)
+	}
+	if og := strings.TrimSpace(doc.Find(`meta[property="og:image"], meta[name="og:image"]`).First().AttrOr("content", "")); og != "" {
+		page.image = resolve(link, og)
+	}
+	return page, nil
+}
+
+// feedCanonical is the link the feed says is the article's permanent one, FeedBurner's original link or a permalink
+// guid which is the same as the item's link. It's empty when the feed doesn't say
+func feedCanonical(article *gofeed.Item) string {
+	for _, origin := range article.Extensions["feedburner"]["origLink"] {
+		if value := strings.TrimSpace(origin.Value); value != "" {
+			return value
+		}
+	}
+	if article.Link != "" && article.GUID != "" && canonical.Key(article.GUID) == canonical.Key(article.Link) {
+		return article.Link
+	}
+	return ""
+}
+
+// publishedSince is true when the item was published or updated after since, or since is nil as the feed has never
+// been fetched. Items the feed doesn't date are treated as old
+func publishedSince(article *gofeed.Item, since *time.Time) bool {
+	if since == nil {
+		return true
+	}
+	for _, published := range []*time.Time{article.PublishedParsed, article.UpdatedParsed} {
+		if published != nil && published.After(*since) {
+			return true
+		}
+	}
+	return false
+}
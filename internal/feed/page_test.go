@@ -0,0 +1,174 @@
+package feed
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mmcdole/gofeed"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moynur/news-app/internal/config"
+	"github.com/moynur/news-app/internal/store"
+)
+
+func TestFeeder_fetchPage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/story":
+			_, _ = w.Write([]byte(`<html><head>
+				<link rel="canonical" href="/canonical-story">
+				<meta property="og:image" content="/og.jpg">
+			</head></html>`))
+		case "/plain":
+			_, _ = w.Write([]byte(`<html><head><title>Nothing here</title></head></html>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	f := NewFeeder(nil, config.FeederConfig{FetchTimeout: time.Second})
+
+	page, err := f.fetchPage(context.Background(), srv.URL+"/story")
+	assert.NoError(t, err)
+	assert.Equal(t, articlePage{canonical: srv.URL + "/canonical-story", image: srv.URL + "/og.jpg"}, page)
+
+	page, err = f.fetchPage(context.Background(), srv.URL+"/plain")
+	assert.NoError(t, err)
+	assert.Equal(t, articlePage{}, page)
+
+	_, err = f.fetchPage(context.Background(), srv.URL+"/missing")
+	assert.Error(t, err)
+}
+
+func TestFeeder_LoadAndStoreArticles_Canonical(t *testing.T) {
+	var pages []string
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	published := time.Now().Add(-time.Minute).UTC().Format(time.RFC1123Z)
+	mux.HandleFunc("/feed.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `<rss version="2.0"><channel><title>Example</title>
+			<item><title>New story</title><link>%[1]s/amp/story?utm_source=rss</link><pubDate>%[2]s</pubDate></item>
+			<item><title>Old story</title><link>%[1]s/old</link><pubDate>Mon, 05 Jan 2026 09:00:00 +0000</pubDate></item>
+		</channel></rss>`, srv.URL, published)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		pages = append(pages, r.URL.Path)
+		_, _ = fmt.Fprintf(w, `<html><head><link rel="canonical" href="%s/story"></head></html>`, srv.URL)
+	})
+
+	ctrl := gomock.NewController(t)
+	ms := store.NewMockStorer(ctrl)
+	source := config.FeedConfig{Name: "example", URL: srv.URL + "/feed.xml", RefreshInterval: time.Minute, Enabled: true}
+	f := NewFeeder(ms, config.FeederConfig{FetchTimeout: time.Second, MaxPageFetches: 10})
+
+	lastSuccess := time.Now().Add(-time.Hour)
+	var saved []store.NewsArticle
+	ms.EXPECT().GetFeedState(gomock.Any(), "example").Return(store.FeedState{Name: "example", LastSuccessAt: &lastSuccess}, nil)
+	ms.EXPECT().UpsertArticles(gomock.Any(), gomock.Len(2)).DoAndReturn(func(_ context.Context, articles []store.NewsArticle) (store.UpsertResult, error) {
+		saved = articles
+		return store.UpsertResult{Inserted: 2}, nil
+	})
+	ms.EXPECT().SaveFeedState(gomock.Any(), gomock.Any()).Return(nil)
+	f.loadAndStoreFeed(context.Background(), source)
+
+	// only the page of the article published since the last fetch is read
+	assert.Equal(t, []string{"/amp/story"}, pages)
+	host := srv.Listener.Addr().String()
+	assert.Equal(t, srv.URL+"/story", saved[0].Link)
+	assert.Equal(t, host+"/story", saved[0].LinkKey)
+	assert.Equal(t, []store.ArticleSource{{Source: "example", LinkKey: host + "/amp/story"}}, saved[0].Sources)
+	assert.Equal(t, srv.URL+"/old", saved[1].Link)
+	assert.Equal(t, []store.ArticleSource{{Source: "example", LinkKey: host + "/old"}}, saved[1].Sources)
+}
+
+func TestFeeder_LoadAndStoreArticles_PageLimits(t *testing.T) {
+	t.Run("only reads pages the feed doesn't say enough about, up to the limit", func(t *testing.T) {
+		var pages []string
+		mux := http.NewServeMux()
+		srv := httptest.NewServer(mux)
+		defer srv.Close()
+		mux.HandleFunc("/feed.xml", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprintf(w, `<rss version="2.0" xmlns:feedburner="http://rssnamespace.org/feedburner/ext/1.0"><channel>
+				<title>Example</title>
+				<item><title>Pictured</title><link>%[1]s/pictured</link><enclosure url="%[1]s/a.jpg" type="image/jpeg" length="1"/></item>
+				<item><title>Proxied</title><link>http://proxy.example.com/1</link><feedburner:origLink>%[1]s/original</feedburner:origLink></item>
+				<item><title>Permalink</title><link>%[1]s/permalink</link><guid>%[1]s/permalink</guid></item>
+				<item><title>First</title><link>%[1]s/first</link></item>
+				<item><title>Second</title><link>%[1]s/second</link></item>
+				<item><title>Third</title><link>%[1]s/third</link></item>
+			</channel></rss>`, srv.URL)
+		})
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			pages = append(pages, r.URL.Path)
+			_, _ = w.Write([]byte(`<html><head></head></html>`))
+		})
+
+		ctrl := gomock.NewController(t)
+		ms := store.NewMockStorer(ctrl)
+		source := config.FeedConfig{Name: "example", URL: srv.URL + "/feed.xml", RefreshInterval: time.Minute, Enabled: true}
+		f := NewFeeder(ms, config.FeederConfig{FetchTimeout: time.Second, MaxPageFetches: 2})
+
+		var saved []store.NewsArticle
+		// it's never been fetched so every item is new
+		ms.EXPECT().GetFeedState(gomock.Any(), "example").Return(store.FeedState{Name: "example"}, nil)
+		ms.EXPECT().UpsertArticles(gomock.Any(), gomock.Len(6)).DoAndReturn(func(_ context.Context, articles []store.NewsArticle) (store.UpsertResult, error) {
+			saved = articles
+			return store.UpsertResult{Inserted: 6}, nil
+		})
+		ms.EXPECT().SaveFeedState(gomock.Any(), gomock.Any()).Return(nil)
+		f.loadAndStoreFeed(context.Background(), source)
+
+		assert.Equal(t, []string{"/first", "/second"}, pages)
+		assert.Equal(t, srv.URL+"/original", saved[1].Link, "the feed gave the original link")
+	})
+
+	t.Run("reads every page within the fetch timeout", func(t *testing.T) {
+		var pages int32
+		mux := http.NewServeMux()
+		srv := httptest.NewServer(mux)
+		defer srv.Close()
+		mux.HandleFunc("/feed.xml", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprintf(w, `<rss version="2.0"><channel><title>Example</title>
+				<item><title>First</title><link>%[1]s/first</link></item>
+				<item><title>Second</title><link>%[1]s/second</link></item>
+			</channel></rss>`, srv.URL)
+		})
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&pages, 1)
+			// the page never loads
+			<-r.Context().Done()
+		})
+
+		ctrl := gomock.NewController(t)
+		ms := store.NewMockStorer(ctrl)
+		source := config.FeedConfig{Name: "example", URL: srv.URL + "/feed.xml", RefreshInterval: time.Minute, Enabled: true}
+		f := NewFeeder(ms, config.FeederConfig{FetchTimeout: 200 * time.Millisecond, MaxPageFetches: 10})
+
+		ms.EXPECT().GetFeedState(gomock.Any(), "example").Return(store.FeedState{Name: "example"}, nil)
+		ms.EXPECT().UpsertArticles(gomock.Any(), gomock.Len(2)).Return(store.UpsertResult{Inserted: 2}, nil)
+		ms.EXPECT().SaveFeedState(gomock.Any(), gomock.Any()).Return(nil)
+		started := time.Now()
+		f.loadAndStoreFeed(context.Background(), source)
+
+		assert.Less(t, time.Since(started), time.Second)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&pages), "the second page isn't read once the time is up")
+	})
+}
+
+func Test_publishedSince(t *testing.T) {
+	since := time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC)
+	before := since.Add(-time.Hour)
+	after := since.Add(time.Hour)
+	assert.True(t, publishedSince(&gofeed.Item{}, nil))
+	assert.True(t, publishedSince(&gofeed.Item{PublishedParsed: &after}, &since))
+	assert.True(t, publishedSince(&gofeed.Item{PublishedParsed: &before, UpdatedParsed: &after}, &since))
+	assert.False(t, publishedSince(&gofeed.Item{PublishedParsed: &before}, &since))
+	assert.False(t, publishedSince(&gofeed.Item{}, &since))
+}
@@ -0,0 +1,95 @@
+package feed
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/moynur/news-app/internal/config"
+	"github.com/moynur/news-app/internal/store"
+)
+
+// polledFeed is a feed which is being polled, closing stop stops it once any poll in progress has finished
+type polledFeed struct {
+	source config.FeedConfig
+	stop   chan struct{}
+}
+
+// SeedFeeds adds the feeds from config which haven't been added before, once a feed exists it is managed through the
+// admin API and changing it in config has no effect
+func (s *feeder) SeedFeeds(ctx context.Context, sources []config.FeedConfig) error {
+	feeds := make([]store.Feed, 0, len(sources))
+	for _, source := range sources {
+		feeds = append(feeds, store.Feed{
+			Name:                   source.Name,
+			URL:                    source.URL,
+			RefreshIntervalSeconds: int(source.RefreshInterval / time.Second),
+			DefaultCategory:        source.DefaultCategory,
+			FallbackImage:          source.FallbackImage,
+			Enabled:                source.Enabled,
+		})
+	}
+	err := s.store.CreateFeedsIfNotExist(ctx, feeds)
+	if err != nil {
+		return fmt.Errorf("unable to seed feeds, %w", err)
+	}
+	return nil
+}
+
+// loadSources returns every feed which has been added, enabled or not
+func (s *feeder) loadSources(ctx context.Context) ([]config.FeedConfig, error) {
+	feeds, err := s.store.GetFeeds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sources := make([]config.FeedConfig, 0, len(feeds))
+	for _, feed := range feeds {
+		sources = append(sources, config.FeedConfig{
+			Name:            feed.Name,
+			URL:             feed.URL,
+			RefreshInterval: time.Duration(feed.RefreshIntervalSeconds) * time.Second,
+			DefaultCategory: feed.DefaultCategory,
+			FallbackImage:   feed.FallbackImage,
+			Enabled:         feed.Enabled,
+		})
+	}
+	return sources, nil
+}
+
+// reload starts polling feeds which have been added or enabled and stops polling the ones which have been removed or
+// disabled, a feed which has changed is stopped and started again with its new settings
+func (s *feeder) reload(ctx context.Context, polling map[string]*polledFeed) {
+	sources, err := s.loadSources(ctx)
+	if err != nil {
+		log.Println("unable to reload feeds, carrying on with the current ones", err)
+		return
+	}
+	wanted := make(map[string]config.FeedConfig, len(sources))
+	for _, source := range sources {
+		if source.Enabled && source.RefreshInterval > 0 {
+			wanted[source.Name] = source
+		}
+	}
+	for name, polled := range polling {
+		if source, ok := wanted[name]; ok && source == polled.source {
+			continue
+		}
+		log.Println("no longer polling feed", name)
+		close(polled.stop)
+		delete(polling, name)
+	}
+	for name, source := range wanted {
+		if _, ok := polling[name]; ok {
+			continue
+		}
+		log.Println("polling feed", name)
+		polled := &polledFeed{source: source, stop: make(chan struct{})}
+		polling[name] = polled
+		s.running.Add(1)
+		go func() {
+			defer s.running.Done()
+			s.refreshFeed(ctx, polled.source, polled.stop)
+		}()
+	}
+}
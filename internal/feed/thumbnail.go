@@ -0,0 +1,129 @@
+package feed
+
+import (
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
+)
+
+// minImageSize filters out tracking pixels and icons, images without a size are assumed to be big enough
+const minImageSize = 100
+
+type image struct {
+	url    string
+	width  int
+	height int
+}
+
+// thumbnail picks the largest image the publisher gave for the item from its media extensions, enclosures, image and
+// the first image in its content. Images without a size lose to any with one, otherwise the first one found is used
+func thumbnail(article *gofeed.Item) string {
+	var best image
+	for _, candidate := range images(article) {
+		if candidate.url == "" || tooSmall(candidate) {
+			continue
+		}
+		if best.url == "" || candidate.width*candidate.height > best.width*best.height {
+			best = candidate
+		}
+	}
+	return best.url
+}
+
+func images(article *gofeed.Item) []image {
+	var found []image
+	media := article.Extensions["media"]
+	for _, group := range media["group"] {
+		found = append(found, mediaImages(group.Children)...)
+	}
+	found = append(found, mediaImages(media)...)
+	for _, enclosure := range article.Enclosures {
+		if enclosure != nil && strings.HasPrefix(enclosure.Type, "image/") {
+			found = append(found, image{url: enclosure.URL})
+		}
+	}
+	if article.Image != nil {
+		found = append(found, image{url: article.Image.URL})
+	}
+	content := article.Content
+	if content == "" {
+		content = article.Description
+	}
+	if img, ok := contentImage(content, articleLink(article)); ok {
+		found = append(found, img)
+	}
+	return found
+}
+
+// mediaImages reads media:thumbnail and media:content, media:content is only used when it's an image
+func mediaImages(media map[string][]ext.Extension) []image {
+	var found []image
+	for _, thumbnail := range media["thumbnail"] {
+		found = append(found, mediaImage(thumbnail))
+	}
+	for _, content := range media["content"] {
+		medium := content.Attrs["medium"]
+		mimeType := content.Attrs["type"]
+		if medium == "image" || strings.HasPrefix(mimeType, "image/") || (medium == "" && mimeType == "" && isImageURL(content.Attrs["url"])) {
+			found = append(found, mediaImage(content))
+		}
+	}
+	return found
+}
+
+func mediaImage(media ext.Extension) image {
+	width, _ := strconv.Atoi(media.Attrs["width"])
+	height, _ := strconv.Atoi(media.Attrs["height"])
+	return image{url: media.Attrs["url"], width: width, height: height}
+}
+
+// contentImage is the first image in the item's html, relative urls are resolved against the article's link
+func contentImage(content string, link string) (image, bool) {
+	if !strings.Contains(content, "<img") {
+		return image{}, false
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
+		return image{}, false
+	}
+	img := doc.Find("img[src]").First()
+	if img.Length() == 0 {
+		return image{}, false
+	}
+	width, _ := strconv.Atoi(img.AttrOr("width", ""))
+	height, _ := strconv.Atoi(img.AttrOr("height", ""))
+	return image{url: resolve(link, img.AttrOr("src", "")), width: width, height: height}, true
+}
+
+func tooSmall(img image) bool {
+	return (img.width > 0 && img.width < minImageSize) || (img.height > 0 && img.height < minImageSize)
+}
+
+func isImageURL(raw string) bool {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(path.Ext(parsed.Path)) {
+	case ".jpg", ".jpeg", ".png", ".gif", ".webp", ".avif":
+		return true
+	}
+	return false
+}
+
+func resolve(base string, ref string) string {
+	baseURL, err := url.Parse(base)
+	if err != nil || base == "" {
+		return ref
+	}
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return baseURL.ResolveReference(refURL).String()
+}
@@ -0,0 +1,69 @@
+package feed
+
+import (
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
+	"github.com/stretchr/testify/assert"
+)
+
+func media(name string, attrs map[string]string) ext.Extension {
+	return ext.Extension{Name: name, Attrs: attrs}
+}
+
+func Test_thumbnail(t *testing.T) {
+	tests := []struct {
+		name string
+		item *gofeed.Item
+		want string
+	}{
+		{
+			name: "largest media image",
+			item: &gofeed.Item{Extensions: ext.Extensions{"media": {
+				"thumbnail": {media("thumbnail", map[string]string{"url": "https://example.com/small.jpg", "width": "200", "height": "100"})},
+				"content": {
+					media("content", map[string]string{"url": "https://example.com/large.jpg", "medium": "image", "width": "1600", "height": "900"}),
+					media("content", map[string]string{"url": "https://example.com/video.mp4", "medium": "video", "width": "1920", "height": "1080"}),
+				},
+			}}},
+			want: "https://example.com/large.jpg",
+		},
+		{
+			name: "media group",
+			item: &gofeed.Item{Extensions: ext.Extensions{"media": {
+				"group": {{Name: "group", Children: map[string][]ext.Extension{
+					"content": {media("content", map[string]string{"url": "https://example.com/grouped.png"})},
+				}}},
+			}}},
+			want: "https://example.com/grouped.png",
+		},
+		{
+			name: "sized image beats unsized image",
+			item: &gofeed.Item{
+				Image:   &gofeed.Image{URL: "https://example.com/unsized.jpg"},
+				Content: `<p><img src="/sized.jpg" width="800" height="600"></p>`,
+				Link:    "https://example.com/story/1",
+			},
+			want: "https://example.com/sized.jpg",
+		},
+		{
+			name: "image enclosure",
+			item: &gofeed.Item{Enclosures: []*gofeed.Enclosure{
+				{URL: "https://example.com/a.mp3", Type: "audio/mpeg"},
+				{URL: "https://example.com/b.jpg", Type: "image/jpeg"},
+			}},
+			want: "https://example.com/b.jpg",
+		},
+		{
+			name: "tracking pixel is skipped",
+			item: &gofeed.Item{Description: `<img src="https://example.com/pixel.gif" width="1" height="1">`},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, thumbnail(tt.item))
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package migrate_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/moynur/news-app/internal/migrate"
+)
+
+func file(contents string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(contents)}
+}
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/0010_add_feeds.up.sql":        file("CREATE TABLE feeds (name TEXT);"),
+		"migrations/0010_add_feeds.down.sql":      file("DROP TABLE feeds;"),
+		"migrations/0002_initial_schema.up.sql":   file("CREATE TABLE news_articles (id SERIAL);"),
+		"migrations/0002_initial_schema.down.sql": file("DROP TABLE news_articles;"),
+	}
+	migrations, err := migrate.Load(fsys, "migrations")
+	require.NoError(t, err)
+	assert.Equal(t, []migrate.Migration{
+		{Version: 2, Name: "initial_schema", Up: "CREATE TABLE news_articles (id SERIAL);", Down: "DROP TABLE news_articles;"},
+		{Version: 10, Name: "add_feeds", Up: "CREATE TABLE feeds (name TEXT);", Down: "DROP TABLE feeds;"},
+	}, migrations)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{
+			name: "missing down",
+			fsys: fstest.MapFS{"migrations/0001_initial.up.sql": file("CREATE TABLE a (id INT);")},
+		},
+		{
+			name: "badly named",
+			fsys: fstest.MapFS{"migrations/initial.sql": file("CREATE TABLE a (id INT);")},
+		},
+		{
+			name: "version zero",
+			fsys: fstest.MapFS{
+				"migrations/0000_initial.up.sql":   file("CREATE TABLE a (id INT);"),
+				"migrations/0000_initial.down.sql": file("DROP TABLE a;"),
+			},
+		},
+		{
+			name: "same version twice",
+			fsys: fstest.MapFS{
+				"migrations/0001_initial.up.sql": file("CREATE TABLE a (id INT);"),
+				"migrations/0001_other.down.sql": file("DROP TABLE a;"),
+			},
+		},
+		{
+			name: "no directory",
+			fsys: fstest.MapFS{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := migrate.Load(tt.fsys, "migrations")
+			assert.Error(t, err)
+		})
+	}
+}
@@ -1,21 +1,159 @@
 package models
 
+import "time"
+
+const (
+	CategoryMatchAny = "any"
+	CategoryMatchAll = "all"
+)
+
+const (
+	SortNewest = "newest"
+	SortOldest = "oldest"
+	// SortIngested is what SortOldest was called before there was SortNewest
+	SortIngested  = "ingested"
+	SortPublished = "published"
+	// SortOldestPublished is SortPublished the other way round
+	SortOldestPublished = "oldest_published"
+	SortRelevance       = "relevance"
+)
+
 type GetArticlesRequest struct {
-	Cursor   int
-	Category string
-	Provider string
-	Title    string
+	// Cursor is the NextCursor, PrevCursor or SinceCursor of another page, it only works with the same filters and sort
+	Cursor     string
+	Category   string
+	Categories []string
+	// CategoryMatch is either CategoryMatchAny or CategoryMatchAll, defaulting to any
+	CategoryMatch string
+	Provider      string
+	Title         string
+	// Query is a full text search, every word has to be in the article. Words are matched by their stem so "storms"
+	// finds "storm", and terms can be combined with AND, OR, NOT, brackets and fields like title:
+	Query string
+	// Sort is SortNewest, SortOldest, SortPublished which is newest published first, SortOldestPublished or
+	// SortRelevance which needs a Query. It defaults to relevance when the Query has words to rank by and newest when
+	// it doesn't
+	Sort            string
+	PublishedAfter  *time.Time
+	PublishedBefore *time.Time
+	// CollapseClusters returns one article per story, with a count of the other articles covering it
+	CollapseClusters bool
 }
 
 type GetArticlesResponse struct {
-	NextCursor int
-	Articles   []Article
+	// NextCursor is an opaque token for loading the page after this one, it's empty on the last page
+	NextCursor string
+	// PrevCursor loads the page before this one, it's empty on the first page
+	PrevCursor string
+	// SinceCursor loads the articles newer than the first on this page, it's only given at the top of the newest
+	// articles. When nothing newer has come in the response has no articles and the same SinceCursor
+	SinceCursor string
+	Articles    []Article
+	// Fuzzy is set when nothing matched the query as it was spelt, so the articles have words spelt like it instead
+	Fuzzy bool
 }
 
 type Article struct {
-	ID       int
-	Title    string
-	Summary  string
-	ImageRef string
-	Link     string
+	ID         int
+	Title      string
+	Summary    string
+	Content    string
+	ImageRef   string
+	Link       string
+	GUID       string
+	Authors    []string
+	Enclosures []Enclosure
+	Provider   string
+	// Sources are the feeds the article has been seen in
+	Sources    []string
+	Categories []string
+	// PublishedAt is as given by the publisher, or when the article was ingested if the feed doesn't say
+	PublishedAt time.Time
+	// UpdatedAt is as given by the publisher and is nil when the feed doesn't say
+	UpdatedAt  *time.Time
+	IngestedAt time.Time
+	// ClusterID is shared by articles about the same story
+	ClusterID int
+	// RelatedCount is how many other articles cover the same story, it's only set when collapsing clusters
+	RelatedCount int
+	// Snippet is only set when searching, it's the summary with the words which matched wrapped in <mark> tags
+	Snippet string
+}
+
+// Enclosure is a file attached to an article, Length is in bytes and zero when it isn't known
+type Enclosure struct {
+	URL    string
+	Type   string
+	Length int64
+}
+
+// ArticleRevision is what an article looked like before it was changed, ReplacedAt is when it changed
+type ArticleRevision struct {
+	Title      string
+	Summary    string
+	ImageRef   string
+	ReplacedAt time.Time
+}
+
+const (
+	SuggestionCategory = "category"
+	SuggestionProvider = "provider"
+	SuggestionTitle    = "title"
+)
+
+// Suggestion is something to search for which starts with what's been typed, Kind is what it's the name of
+type Suggestion struct {
+	Text string
+	Kind string
+}
+
+// FeedStatus is the health of a feed and what happened the last time it was fetched
+type FeedStatus struct {
+	Name                string
+	URL                 string
+	Enabled             bool
+	Paused              bool
+	LastFetchedAt       *time.Time
+	LastSuccessAt       *time.Time
+	LastStatus          int
+	ItemsSeen           int
+	ItemsInserted       int
+	ItemsUpdated        int
+	DuplicatesSkipped   int
+	ConsecutiveFailures int
+	LastError           string
+	NextRunAt           *time.Time
+}
+
+// Feed is a source of articles, feeds are identified by their name
+type Feed struct {
+	Name            string
+	URL             string
+	RefreshInterval time.Duration
+	DefaultCategory string
+	// FallbackImage is the thumbnail given to articles when no image can be found for them
+	FallbackImage string
+	Enabled       bool
+}
+
+// FeedUpdate changes only the fields of a feed which are set
+type FeedUpdate struct {
+	URL             *string
+	RefreshInterval *time.Duration
+	DefaultCategory *string
+	FallbackImage   *string
+	Enabled         *bool
+}
+
+// FeedImport is what happened to each feed in an OPML import
+type FeedImport struct {
+	Created []Feed
+	// Skipped are the urls of feeds which had already been added
+	Skipped []string
+	Failed  []FeedImportFailure
+}
+
+type FeedImportFailure struct {
+	URL    string
+	Reason string
 }
@@ -0,0 +1,163 @@
+package opml
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+var ErrNoSubscriptions = errors.New("opml document has no feed subscriptions")
+
+// Subscription is a single feed from an OPML document
+type Subscription struct {
+	Title   string
+	XMLURL  string
+	HTMLURL string
+	// Categories come from the outline's category attribute followed by the folders it is nested in
+	Categories []string
+}
+
+type document struct {
+	XMLName xml.Name  `xml:"opml"`
+	Version string    `xml:"version,attr"`
+	Head    head      `xml:"head"`
+	Body    []outline `xml:"body>outline"`
+}
+
+type head struct {
+	Title       string `xml:"title,omitempty"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+}
+
+type outline struct {
+	Text     string    `xml:"text,attr"`
+	Title    string    `xml:"title,attr,omitempty"`
+	Type     string    `xml:"type,attr,omitempty"`
+	XMLURL   string    `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string    `xml:"htmlUrl,attr,omitempty"`
+	Category string    `xml:"category,attr,omitempty"`
+	Outlines []outline `xml:"outline"`
+}
+
+// Parse reads every feed subscription from an OPML document, outlines without an xmlUrl are treated as folders
+func Parse(r io.Reader) ([]Subscription, error) {
+	var doc document
+	err := xml.NewDecoder(r).Decode(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse opml, %w", err)
+	}
+	var subscriptions []Subscription
+	walk(doc.Body, nil, &subscriptions)
+	if len(subscriptions) == 0 {
+		return nil, ErrNoSubscriptions
+	}
+	return subscriptions, nil
+}
+
+func walk(outlines []outline, folders []string, subscriptions *[]Subscription) {
+	for _, o := range outlines {
+		title := o.Title
+		if title == "" {
+			title = o.Text
+		}
+		if o.XMLURL == "" {
+			walk(o.Outlines, append(folders[:len(folders):len(folders)], title), subscriptions)
+			continue
+		}
+		*subscriptions = append(*subscriptions, Subscription{
+			Title:      title,
+			XMLURL:     strings.TrimSpace(o.XMLURL),
+			HTMLURL:    o.HTMLURL,
+			Categories: unique(append(categories(o.Category), reverse(folders)...)),
+		})
+	}
+}
+
+// categories splits the OPML category attribute, a comma separated list of slash delimited paths where the last part
+// of each path is the most specific category
+func categories(attr string) []string {
+	var names []string
+	for _, path := range strings.Split(attr, ",") {
+		parts := strings.Split(strings.Trim(strings.TrimSpace(path), "/"), "/")
+		if name := strings.TrimSpace(parts[len(parts)-1]); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// reverse puts the folder a feed is directly in first as it's the most specific
+func reverse(folders []string) []string {
+	reversed := make([]string, 0, len(folders))
+	for i := len(folders) - 1; i >= 0; i-- {
+		reversed = append(reversed, folders[i])
+	}
+	return reversed
+}
+
+// unique removes repeated categories, keeping the first so the most specific category stays first
+func unique(names []string) []string {
+	var result []string
+	seen := map[string]bool{}
+	for _, name := range names {
+		if seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		result = append(result, name)
+	}
+	return result
+}
+
+// Write creates an OPML document of the subscriptions, subscriptions are put in a folder for their first category so
+// they are grouped the same way when imported into a desktop reader
+func Write(w io.Writer, title string, subscriptions []Subscription) error {
+	doc := document{
+		Version: "2.0",
+		Head:    head{Title: title, DateCreated: time.Now().UTC().Format(time.RFC1123Z)},
+	}
+	folders := map[string]*outline{}
+	var folderNames []string
+	for _, subscription := range subscriptions {
+		o := outline{
+			Text:     subscription.Title,
+			Title:    subscription.Title,
+			Type:     "rss",
+			XMLURL:   subscription.XMLURL,
+			HTMLURL:  subscription.HTMLURL,
+			Category: strings.Join(subscription.Categories, ","),
+		}
+		if len(subscription.Categories) == 0 {
+			doc.Body = append(doc.Body, o)
+			continue
+		}
+		name := subscription.Categories[0]
+		folder, ok := folders[name]
+		if !ok {
+			folder = &outline{Text: name, Title: name}
+			folders[name] = folder
+			folderNames = append(folderNames, name)
+		}
+		folder.Outlines = append(folder.Outlines, o)
+	}
+	sort.Strings(folderNames)
+	for _, name := range folderNames {
+		doc.Body = append(doc.Body, *folders[name])
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return fmt.Errorf("unable to write opml, %w", err)
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(doc)
+	if err != nil {
+		return fmt.Errorf("unable to write opml, %w", err)
+	}
+	return nil
+}
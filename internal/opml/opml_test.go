@@ -0,0 +1,64 @@
+package opml_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moynur/news-app/internal/opml"
+)
+
+const desktopExport = `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="1.0">
+	<head><title>Subscriptions</title></head>
+	<body>
+		<outline text="Sky News" type="rss" xmlUrl=" http://feeds.skynews.com/feeds/rss/home.xml " htmlUrl="https://news.sky.com"/>
+		<outline text="UK" title="UK">
+			<outline text="Politics">
+				<outline text="BBC Politics" type="rss" xmlUrl="https://feeds.bbci.co.uk/news/politics/rss.xml"/>
+			</outline>
+			<outline text="Guardian" type="rss" xmlUrl="https://www.theguardian.com/uk/rss" category="/News/Breaking,/Opinion"/>
+		</outline>
+		<outline text="Empty folder"/>
+	</body>
+</opml>`
+
+func TestParse(t *testing.T) {
+	got, err := opml.Parse(strings.NewReader(desktopExport))
+	assert.NoError(t, err)
+	assert.Equal(t, []opml.Subscription{
+		{Title: "Sky News", XMLURL: "http://feeds.skynews.com/feeds/rss/home.xml", HTMLURL: "https://news.sky.com"},
+		{Title: "BBC Politics", XMLURL: "https://feeds.bbci.co.uk/news/politics/rss.xml", Categories: []string{"Politics", "UK"}},
+		{Title: "Guardian", XMLURL: "https://www.theguardian.com/uk/rss", Categories: []string{"Breaking", "Opinion", "UK"}},
+	}, got)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	_, err := opml.Parse(strings.NewReader("not opml"))
+	assert.Error(t, err)
+
+	_, err = opml.Parse(strings.NewReader(`<opml version="2.0"><body><outline text="folder"/></body></opml>`))
+	assert.ErrorIs(t, err, opml.ErrNoSubscriptions)
+}
+
+func TestWrite(t *testing.T) {
+	subscriptions := []opml.Subscription{
+		{Title: "sky-news-uk", XMLURL: "http://feeds.skynews.com/feeds/rss/uk.xml", Categories: []string{"uk"}},
+		{Title: "bbc-news", XMLURL: "https://feeds.bbci.co.uk/news/rss.xml"},
+		{Title: "sky-news-world", XMLURL: "http://feeds.skynews.com/feeds/rss/world.xml", Categories: []string{"uk"}},
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, opml.Write(&buf, "news-app feeds", subscriptions))
+	assert.Contains(t, buf.String(), `<outline text="uk" title="uk">`)
+
+	// an export can be imported again
+	got, err := opml.Parse(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, []opml.Subscription{
+		{Title: "bbc-news", XMLURL: "https://feeds.bbci.co.uk/news/rss.xml"},
+		{Title: "sky-news-uk", XMLURL: "http://feeds.skynews.com/feeds/rss/uk.xml", Categories: []string{"uk"}},
+		{Title: "sky-news-world", XMLURL: "http://feeds.skynews.com/feeds/rss/world.xml", Categories: []string{"uk"}},
+	}, got)
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/moynur/news-app/internal/models"
+)
+
+var (
+	ErrInvalidCursor  = errors.New("cursor is invalid, it has to be one this service returned")
+	ErrCursorMismatch = errors.New("cursor was returned for a different search, it can only be used with the same filters and sort")
+)
+
+const (
+	cursorVersion = 2
+	// directionNext is a cursor for the articles after the last one returned
+	directionNext = "next"
+	// directionPrev is a cursor for the articles before the first one returned
+	directionPrev = "prev"
+	// directionSince is a cursor for the articles newer than the first one returned, which is the newest the client has
+	directionSince = "since"
+)
+
+// cursor is where a page of articles ended. Clients are given it sealed with AES-GCM so they can't read the article ID
+// in it or change it, and it can only be used with the filters and sort it was returned for
+type cursor struct {
+	// Version is changed with the format so cursors clients already have can be told apart
+	Version int `json:"v"`
+	// Sort is the sort the page was in, as one of the models sorts
+	Sort      string `json:"s"`
+	Direction string `json:"d"`
+	// ID is the article the page ended or started with, the store finds where it was in the sort order
+	ID int `json:"id"`
+	// Filters is a hash of the filters the cursor was returned for
+	Filters []byte `json:"f"`
+	// Fuzzy is set when the page was a fuzzy search so the next one is too
+	Fuzzy bool `json:"z,omitempty"`
+}
+
+// cursorFilters are the filters of a request in a canonical form, hashed into the cursor
+type cursorFilters struct {
+	Categories       []string   `json:"categories"`
+	MatchAll         bool       `json:"match_all"`
+	Provider         string     `json:"provider"`
+	Title            string     `json:"title"`
+	Query            string     `json:"query"`
+	PublishedAfter   *time.Time `json:"published_after"`
+	PublishedBefore  *time.Time `json:"published_before"`
+	CollapseClusters bool       `json:"collapse_clusters"`
+}
+
+// filtersHash is the same for requests with the same filters, however they were written
+func filtersHash(req models.GetArticlesRequest, matchAll bool) []byte {
+	names := categories(req)
+	sort.Strings(names)
+	filters := cursorFilters{
+		Categories:       names,
+		MatchAll:         matchAll && len(names) > 0,
+		Provider:         req.Provider,
+		Title:            req.Title,
+		Query:            strings.TrimSpace(req.Query),
+		PublishedAfter:   utc(req.PublishedAfter),
+		PublishedBefore:  utc(req.PublishedBefore),
+		CollapseClusters: req.CollapseClusters,
+	}
+	// the struct only has values json can encode
+	encoded, _ := json.Marshal(filters)
+	hash := sha256.Sum256(encoded)
+	return hash[:16]
+}
+
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	converted := t.UTC()
+	return &converted
+}
+
+// sealCursor encrypts the cursor, the nonce is random so the same position gives a different token each time
+func (s *service) sealCursor(c cursor) (string, error) {
+	c.Version = cursorVersion
+	plaintext, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("unable to encode cursor, %w", err)
+	}
+	nonce := make([]byte, s.cursorAEAD.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("unable to make cursor nonce, %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(s.cursorAEAD.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+// openCursor decrypts a cursor, any token this service didn't seal with the same secret is invalid
+func (s *service) openCursor(token string) (cursor, error) {
+	var c cursor
+	sealed, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(sealed) < s.cursorAEAD.NonceSize() {
+		return c, ErrInvalidCursor
+	}
+	nonceSize := s.cursorAEAD.NonceSize()
+	plaintext, err := s.cursorAEAD.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(plaintext, &c); err != nil || c.Version != cursorVersion || c.ID <= 0 {
+		return c, ErrInvalidCursor
+	}
+	switch c.Direction {
+	case directionNext, directionPrev, directionSince:
+	default:
+		return c, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// newCursorAEAD derives the cursor key from the secret, which can be any length
+func newCursorAEAD(secret []byte) cipher.AEAD {
+	key := sha256.Sum256(secret)
+	// a 32 byte key is always valid for AES-256 and GCM always accepts an AES block
+	block, _ := aes.NewCipher(key[:])
+	aead, _ := cipher.NewGCM(block)
+	return aead
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/moynur/news-app/internal/models"
+	"github.com/moynur/news-app/internal/store"
+)
+
+var (
+	ErrArticleNotFound = errors.New("no article found with that id")
+)
+
+// GetArticleRevisions returns what the article looked like before each time the publisher changed it, newest first
+func (s *service) GetArticleRevisions(ctx context.Context, id int) ([]models.ArticleRevision, error) {
+	revisions, err := s.store.GetArticleRevisions(ctx, id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrArticleNotFound
+		}
+		return nil, err
+	}
+	mapped := make([]models.ArticleRevision, 0, len(revisions))
+	for _, revision := range revisions {
+		mapped = append(mapped, models.ArticleRevision{
+			Title:      revision.Title,
+			Summary:    revision.Description,
+			ImageRef:   revision.Thumbnail,
+			ReplacedAt: revision.CreatedAt,
+		})
+	}
+	return mapped, nil
+}
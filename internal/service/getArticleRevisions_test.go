@@ -0,0 +1,55 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moynur/news-app/internal/models"
+	"github.com/moynur/news-app/internal/service"
+	"github.com/moynur/news-app/internal/store"
+)
+
+func Test_service_GetArticleRevisions(t *testing.T) {
+	t.Run("maps the revisions", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := store.NewMockStorer(ctrl)
+		s := service.NewService(ms, cursorSecret)
+		replaced := time.Date(2026, 1, 2, 9, 30, 0, 0, time.UTC)
+		ms.EXPECT().GetArticleRevisions(gomock.Any(), 7).Return([]store.ArticleRevision{
+			{ID: 2, ArticleID: 7, Title: "second headline", Description: "summary", Thumbnail: "image", CreatedAt: replaced},
+			{ID: 1, ArticleID: 7, Title: "first headline", CreatedAt: replaced.Add(-time.Minute)},
+		}, nil)
+
+		got, err := s.GetArticleRevisions(context.Background(), 7)
+		assert.NoError(t, err)
+		assert.Equal(t, []models.ArticleRevision{
+			{Title: "second headline", Summary: "summary", ImageRef: "image", ReplacedAt: replaced},
+			{Title: "first headline", ReplacedAt: replaced.Add(-time.Minute)},
+		}, got)
+	})
+
+	t.Run("returns an empty list for an article which has never changed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := store.NewMockStorer(ctrl)
+		s := service.NewService(ms, cursorSecret)
+		ms.EXPECT().GetArticleRevisions(gomock.Any(), 7).Return(nil, nil)
+
+		got, err := s.GetArticleRevisions(context.Background(), 7)
+		assert.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("returns not found for an unknown article", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := store.NewMockStorer(ctrl)
+		s := service.NewService(ms, cursorSecret)
+		ms.EXPECT().GetArticleRevisions(gomock.Any(), 7).Return(nil, store.ErrNotFound)
+
+		_, err := s.GetArticleRevisions(context.Background(), 7)
+		assert.ErrorIs(t, err, service.ErrArticleNotFound)
+	})
+}
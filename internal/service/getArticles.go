@@ -1,46 +1,223 @@
 package service
 
 import (
+	"bytes"
+	"context"
 	"errors"
-	"log"
 
 	"github.com/moynur/news-app/internal/models"
 	"github.com/moynur/news-app/internal/store"
 )
 
 var (
-	ErrNotFound = errors.New("no articles found matching criteria")
+	ErrNotFound             = errors.New("no articles found matching criteria")
+	ErrInvalidCategoryMatch = errors.New("category match must be either any or all")
+	ErrInvalidSort          = errors.New("sort must be newest, oldest, published, oldest_published or relevance")
+	ErrRelevanceNeedsQuery  = errors.New("sorting by relevance needs a search query with words to rank by")
 )
 
-func (s *service) GetArticles(req models.GetArticlesRequest) (models.GetArticlesResponse, error) {
-	log.Println("reached service")
+// number of records could be a config or a parameter from the client request
+const pageSize = 3
+
+func (s *service) GetArticles(ctx context.Context, req models.GetArticlesRequest) (models.GetArticlesResponse, error) {
 	var response models.GetArticlesResponse
-	// number of records could be a config or a parameter from the client request
-	articles, err := s.store.GetRecordsAfterID(req.Cursor, 3, store.Filters{
+	var matchAll bool
+	switch req.CategoryMatch {
+	case "", models.CategoryMatchAny:
+	case models.CategoryMatchAll:
+		matchAll = true
+	default:
+		return response, ErrInvalidCategoryMatch
+	}
+	condition, err := ParseQuery(req.Query)
+	if err != nil {
+		return response, err
+	}
+	// a query of only fields like provider:sky has no words to rank articles by
+	ranked := condition != nil && condition.Ranked()
+	// name is the sort as a cursor records it, the same for each way of asking for it
+	name := req.Sort
+	var sort store.SortOrder
+	var descending bool
+	switch req.Sort {
+	case "":
+		if ranked {
+			name, sort = models.SortRelevance, store.SortRelevance
+		} else {
+			name, descending = models.SortNewest, true
+		}
+	case models.SortNewest:
+		descending = true
+	case models.SortOldest, models.SortIngested:
+		// ingested was the only order before newest first, clients asking for it still get oldest first
+		name = models.SortOldest
+	case models.SortPublished:
+		sort, descending = store.SortPublished, true
+	case models.SortOldestPublished:
+		sort = store.SortPublished
+	case models.SortRelevance:
+		if !ranked {
+			return response, ErrRelevanceNeedsQuery
+		}
+		sort = store.SortRelevance
+	default:
+		return response, ErrInvalidSort
+	}
+	hash := filtersHash(req, matchAll)
+	var from cursor
+	if req.Cursor != "" {
+		from, err = s.openCursor(req.Cursor)
+		if err != nil {
+			return response, err
+		}
+		if from.Sort != name || !bytes.Equal(from.Filters, hash) {
+			return response, ErrCursorMismatch
+		}
+	}
+	// prev and since pages are read backwards from the cursor so they're the articles closest to it
+	before := from.Direction == directionPrev || from.Direction == directionSince
+	filters := store.Filters{
 		// haven't implemented others but this is to showcase how the filters work
-		Title:         req.Title,
-		Description:   "",
-		Link:          "",
-		Category:      req.Category,
-		CreatedAfter:  nil,
-		CreatedBefore: nil,
-	})
+		Title:              req.Title,
+		Description:        "",
+		Link:               "",
+		Categories:         categories(req),
+		MatchAllCategories: matchAll,
+		Provider:           req.Provider,
+		CreatedAfter:       nil,
+		CreatedBefore:      nil,
+		PublishedAfter:     req.PublishedAfter,
+		PublishedBefore:    req.PublishedBefore,
+		Sort:               sort,
+		Descending:         descending,
+		Before:             before,
+		CollapseClusters:   req.CollapseClusters,
+		Condition:          condition,
+		Fuzzy:              from.Fuzzy,
+	}
+	// one more than a page is read to know whether there are more past it
+	articles, err := s.store.GetRecordsAfterID(ctx, from.ID, pageSize+1, filters)
 	if err != nil {
 		return response, err
 	}
+	// one misspelt word finds nothing, so when the first page of a search is empty it's tried again matching words
+	// spelt like the query's
+	if len(articles) == 0 && condition != nil && req.Cursor == "" {
+		filters.Fuzzy = true
+		articles, err = s.store.GetRecordsAfterID(ctx, 0, pageSize+1, filters)
+		if err != nil {
+			return response, err
+		}
+	}
+	more := len(articles) > pageSize
+	if more && before {
+		// the one furthest from the cursor is dropped
+		articles = articles[1:]
+	} else if more {
+		articles = articles[:pageSize]
+	}
+	if len(articles) == 0 && from.Direction == directionSince {
+		// nothing is newer than the client's head yet, it can ask again with the same cursor
+		response.SinceCursor = req.Cursor
+		return response, nil
+	}
 	if len(articles) == 0 {
 		return response, ErrNotFound
 	}
 	for _, article := range articles {
 		response.Articles = append(response.Articles, models.Article{
-			ID:       int(article.ID),
-			Title:    article.Title,
-			Summary:  article.Description,
-			ImageRef: article.Thumbnail,
-			Link:     article.Link,
+			ID:           int(article.ID),
+			Title:        article.Title,
+			Summary:      article.Description,
+			Content:      article.Content,
+			ImageRef:     article.Thumbnail,
+			Link:         article.Link,
+			GUID:         article.GUID,
+			Authors:      article.Authors,
+			Enclosures:   enclosures(article.Enclosures),
+			Provider:     article.Provider,
+			Sources:      sourceNames(article.Sources),
+			Categories:   categoryNames(article.Categories),
+			PublishedAt:  article.PublishedAt,
+			UpdatedAt:    article.SourceUpdatedAt,
+			IngestedAt:   article.CreatedAt,
+			ClusterID:    int(article.ClusterID),
+			RelatedCount: article.Related,
+			Snippet:      article.Headline,
+		})
+	}
+	first, last := response.Articles[0].ID, response.Articles[len(response.Articles)-1].ID
+	for _, page := range []struct {
+		token     *string
+		direction string
+		id        int
+		given     bool
+	}{
+		// going forwards there's a next page when more were found, going back it's the page the client came from
+		{token: &response.NextCursor, direction: directionNext, id: last, given: more && !before || from.Direction == directionPrev},
+		{token: &response.PrevCursor, direction: directionPrev, id: first, given: more && before && from.Direction == directionPrev || from.Direction == directionNext},
+		// only the top of the newest articles can have newer ones come in above it
+		{token: &response.SinceCursor, direction: directionSince, id: first, given: name == models.SortNewest &&
+			(req.Cursor == "" || from.Direction == directionSince || from.Direction == directionPrev && !more)},
+	} {
+		if !page.given {
+			continue
+		}
+		*page.token, err = s.sealCursor(cursor{
+			Sort:      name,
+			Direction: page.direction,
+			ID:        page.id,
+			Filters:   hash,
+			Fuzzy:     filters.Fuzzy,
 		})
+		if err != nil {
+			return models.GetArticlesResponse{}, err
+		}
 	}
-	response.NextCursor = response.Articles[len(response.Articles)-1].ID
-	log.Println("response", response.NextCursor)
+	response.Fuzzy = filters.Fuzzy
 	return response, nil
 }
+
+// categories merges the single category with the list of categories so both can be used at once, the same
+// category is only ever filtered on once
+func categories(req models.GetArticlesRequest) []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, category := range append([]string{req.Category}, req.Categories...) {
+		category = store.NormaliseCategory(category)
+		if category == "" || seen[category] {
+			continue
+		}
+		seen[category] = true
+		names = append(names, category)
+	}
+	return names
+}
+
+func categoryNames(categories []store.Category) []string {
+	var names []string
+	for _, category := range categories {
+		names = append(names, category.Name)
+	}
+	return names
+}
+
+func sourceNames(sources []store.ArticleSource) []string {
+	var names []string
+	for _, source := range sources {
+		names = append(names, source.Source)
+	}
+	return names
+}
+
+func enclosures(attached store.Enclosures) []models.Enclosure {
+	var mapped []models.Enclosure
+	for _, enclosure := range attached {
+		mapped = append(mapped, models.Enclosure{
+			URL:    enclosure.URL,
+			Type:   enclosure.Type,
+			Length: enclosure.Length,
+		})
+	}
+	return mapped
+}
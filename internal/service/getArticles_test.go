@@ -1,6 +1,7 @@
 package service_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -9,15 +10,38 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/moynur/news-app/internal/models"
 	"github.com/moynur/news-app/internal/service"
 	"github.com/moynur/news-app/internal/store"
 )
 
+var cursorSecret = []byte("cursor secret")
+
+// issueCursor gets a cursor for the request from the service, as if the page before it ended with the article
+func issueCursor(t *testing.T, req models.GetArticlesRequest, id int) string {
+	ctrl := gomock.NewController(t)
+	ms := store.NewMockStorer(ctrl)
+	// a page more is found so the page ends with the article
+	found := []store.NewsArticle{{ID: 1}, {ID: 2}, {ID: uint(id)}, {ID: uint(id) + 1}}
+	ms.EXPECT().GetRecordsAfterID(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(found, nil)
+	req.Cursor = ""
+	resp, err := service.NewService(ms, cursorSecret).GetArticles(context.Background(), req)
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.NextCursor)
+	return resp.NextCursor
+}
+
 func Test_service_GetArticles(t *testing.T) {
+	published := time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC)
+	updated := published.Add(time.Hour)
+	ingested := updated.Add(time.Hour)
 	type args struct {
-		req      models.GetArticlesRequest
+		req models.GetArticlesRequest
+		// cursor is the article the page before ended with, a cursor for it is sent with the request
+		cursor   int
+		filters  store.Filters
 		resp     []store.NewsArticle
 		storeErr error
 	}
@@ -31,63 +55,298 @@ func Test_service_GetArticles(t *testing.T) {
 			name: "correctly maps articles",
 			args: args{
 				req: models.GetArticlesRequest{
-					Cursor:   0,
 					Category: "",
 					Provider: "",
 					Title:    "",
 				},
+				filters: store.Filters{Descending: true},
 				resp: []store.NewsArticle{
 					{
 						ID:          0,
 						Title:       "someTitle",
 						Description: "someDescription",
 						Link:        "someLink",
-						Category:    "someCategory",
+						Categories:  []store.Category{{ID: 1, Name: "somecategory"}},
 						Thumbnail:   "someThumbnail",
-						CreatedAt:   time.Now(),
+						CreatedAt:   ingested,
 					},
 					{
 						ID:          1,
 						Title:       "someTitle",
 						Description: "someDescription",
 						Link:        "someLink",
-						Category:    "someCategory",
+						Categories:  []store.Category{{ID: 1, Name: "somecategory"}},
 						Thumbnail:   "someThumbnail2",
-						CreatedAt:   time.Now(),
+						CreatedAt:   ingested,
+					},
+				},
+				storeErr: nil,
+			},
+			want: models.GetArticlesResponse{
+				Articles: []models.Article{
+					{
+						ID:         0,
+						Title:      "someTitle",
+						Summary:    "someDescription",
+						ImageRef:   "someThumbnail",
+						IngestedAt: ingested,
+						Link:       "someLink",
+						Categories: []string{"somecategory"},
+					},
+					{
+						ID:         1,
+						Title:      "someTitle",
+						Summary:    "someDescription",
+						ImageRef:   "someThumbnail2",
+						IngestedAt: ingested,
+						Link:       "someLink",
+						Categories: []string{"somecategory"},
+					},
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "filters by provider",
+			args: args{
+				req: models.GetArticlesRequest{
+					Category: "",
+					Provider: "Sky News",
+					Title:    "",
+				},
+				filters: store.Filters{
+					Provider:   "Sky News",
+					Descending: true,
+				},
+				resp: []store.NewsArticle{
+					{
+						ID:          4,
+						Title:       "someTitle",
+						Description: "someDescription",
+						Link:        "someLink",
+						Provider:    "Sky News",
+						Thumbnail:   "someThumbnail",
+						CreatedAt:   ingested,
 					},
 				},
 				storeErr: nil,
 			},
 			want: models.GetArticlesResponse{
-				NextCursor: 1,
 				Articles: []models.Article{
 					{
-						ID:       0,
-						Title:    "someTitle",
-						Summary:  "someDescription",
-						ImageRef: "someThumbnail",
-						Link:     "someLink",
+						ID:         4,
+						Title:      "someTitle",
+						Summary:    "someDescription",
+						ImageRef:   "someThumbnail",
+						IngestedAt: ingested,
+						Link:       "someLink",
+						Provider:   "Sky News",
 					},
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "merges and normalises categories",
+			args: args{
+				req: models.GetArticlesRequest{
+					Category:      "Politics",
+					Categories:    []string{" UK ", "politics"},
+					CategoryMatch: models.CategoryMatchAll,
+				},
+				filters: store.Filters{
+					Categories:         []string{"politics", "uk"},
+					MatchAllCategories: true,
+					Descending:         true,
+				},
+				resp: []store.NewsArticle{
+					{
+						ID:         7,
+						Title:      "someTitle",
+						Link:       "someLink",
+						Categories: []store.Category{{ID: 1, Name: "politics"}, {ID: 2, Name: "uk"}},
+					},
+				},
+			},
+			want: models.GetArticlesResponse{
+				Articles: []models.Article{
 					{
-						ID:       1,
-						Title:    "someTitle",
-						Summary:  "someDescription",
-						ImageRef: "someThumbnail2",
-						Link:     "someLink",
+						ID:         7,
+						Title:      "someTitle",
+						Link:       "someLink",
+						Categories: []string{"politics", "uk"},
 					},
 				},
 			},
 			wantErr: assert.NoError,
 		},
+		{
+			name: "maps publisher fields",
+			args: args{
+				filters: store.Filters{Descending: true},
+				resp: []store.NewsArticle{
+					{
+						ID:              9,
+						Title:           "someTitle",
+						Content:         "<p>someContent</p>",
+						Link:            "https://example.com/story",
+						GUID:            "urn:uuid:9",
+						Sources:         []store.ArticleSource{{ArticleID: 9, Source: "sky-news-uk"}, {ArticleID: 9, Source: "sky-news-world"}},
+						Authors:         store.StringList{"Jane Smith"},
+						Enclosures:      store.Enclosures{{URL: "https://example.com/a.mp3", Type: "audio/mpeg", Length: 1024}},
+						PublishedAt:     published,
+						SourceUpdatedAt: &updated,
+					},
+				},
+			},
+			want: models.GetArticlesResponse{
+				Articles: []models.Article{
+					{
+						ID:          9,
+						Title:       "someTitle",
+						Content:     "<p>someContent</p>",
+						Link:        "https://example.com/story",
+						GUID:        "urn:uuid:9",
+						Sources:     []string{"sky-news-uk", "sky-news-world"},
+						Authors:     []string{"Jane Smith"},
+						Enclosures:  []models.Enclosure{{URL: "https://example.com/a.mp3", Type: "audio/mpeg", Length: 1024}},
+						PublishedAt: published,
+						UpdatedAt:   &updated,
+					},
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "sorts and filters by published time",
+			args: args{
+				req: models.GetArticlesRequest{
+					Sort:           models.SortPublished,
+					PublishedAfter: &published,
+				},
+				cursor: 9,
+				filters: store.Filters{
+					PublishedAfter: &published,
+					Sort:           store.SortPublished,
+					Descending:     true,
+				},
+				resp: []store.NewsArticle{
+					{
+						ID:          3,
+						Title:       "someTitle",
+						PublishedAt: updated,
+						CreatedAt:   ingested,
+					},
+				},
+			},
+			want: models.GetArticlesResponse{
+				Articles: []models.Article{
+					{
+						ID:          3,
+						Title:       "someTitle",
+						PublishedAt: updated,
+						IngestedAt:  ingested,
+					},
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "collapses clusters",
+			args: args{
+				req: models.GetArticlesRequest{
+					CollapseClusters: true,
+				},
+				filters: store.Filters{
+					CollapseClusters: true,
+					Descending:       true,
+				},
+				resp: []store.NewsArticle{
+					{
+						ID:          4,
+						Title:       "someTitle",
+						PublishedAt: published,
+						CreatedAt:   ingested,
+						ClusterID:   2,
+						Related:     3,
+					},
+				},
+			},
+			want: models.GetArticlesResponse{
+				Articles: []models.Article{
+					{
+						ID:           4,
+						Title:        "someTitle",
+						PublishedAt:  published,
+						IngestedAt:   ingested,
+						ClusterID:    2,
+						RelatedCount: 3,
+					},
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "searches by relevance with snippets",
+			args: args{
+				req: models.GetArticlesRequest{
+					Query: "economy recession",
+				},
+				filters: store.Filters{
+					Sort:      store.SortRelevance,
+					Condition: &store.Condition{Kind: store.ConditionText, Value: "economy recession"},
+				},
+				resp: []store.NewsArticle{
+					{
+						ID:          7,
+						Title:       "someTitle",
+						Description: "the economy is in recession",
+						Headline:    "the <mark>economy</mark> is in <mark>recession</mark>",
+						CreatedAt:   ingested,
+					},
+				},
+			},
+			want: models.GetArticlesResponse{
+				Articles: []models.Article{
+					{
+						ID:         7,
+						Title:      "someTitle",
+						Summary:    "the economy is in recession",
+						Snippet:    "the <mark>economy</mark> is in <mark>recession</mark>",
+						IngestedAt: ingested,
+					},
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "searches in another order",
+			args: args{
+				req: models.GetArticlesRequest{
+					Query: "economy",
+					Sort:  models.SortPublished,
+				},
+				filters: store.Filters{
+					Condition:  &store.Condition{Kind: store.ConditionText, Value: "economy"},
+					Sort:       store.SortPublished,
+					Descending: true,
+				},
+				resp: []store.NewsArticle{{ID: 2, CreatedAt: ingested}},
+			},
+			want: models.GetArticlesResponse{
+				Articles: []models.Article{{ID: 2, IngestedAt: ingested}},
+			},
+			wantErr: assert.NoError,
+		},
 		{
 			name: "returns error when no articles found",
 			args: args{
 				req: models.GetArticlesRequest{
-					Cursor:   0,
 					Category: "",
 					Provider: "",
 					Title:    "",
 				},
+				filters:  store.Filters{Descending: true},
 				resp:     []store.NewsArticle{},
 				storeErr: nil,
 			},
@@ -98,11 +357,11 @@ func Test_service_GetArticles(t *testing.T) {
 			name: "returns error when store errors",
 			args: args{
 				req: models.GetArticlesRequest{
-					Cursor:   0,
 					Category: "",
 					Provider: "",
 					Title:    "",
 				},
+				filters:  store.Filters{Descending: true},
 				resp:     []store.NewsArticle{},
 				storeErr: errors.New("cant connect to db"),
 			},
@@ -114,22 +373,372 @@ func Test_service_GetArticles(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			ms := store.NewMockStorer(ctrl)
-			s := service.NewService(ms)
-			ms.EXPECT().GetRecordsAfterID(tt.args.req.Cursor, 3, store.Filters{
-				Title:         tt.args.req.Title,
-				Description:   "",
-				Link:          "",
-				Category:      tt.args.req.Category,
-				CreatedAfter:  nil,
-				CreatedBefore: nil,
-			}).Return(tt.args.resp, tt.args.storeErr)
-			got, err := s.GetArticles(tt.args.req)
+			s := service.NewService(ms, cursorSecret)
+			if tt.args.cursor > 0 {
+				tt.args.req.Cursor = issueCursor(t, tt.args.req, tt.args.cursor)
+			}
+			ms.EXPECT().GetRecordsAfterID(gomock.Any(), tt.args.cursor, 4, tt.args.filters).Return(tt.args.resp, tt.args.storeErr)
+			got, err := s.GetArticles(context.Background(), tt.args.req)
 			log.Println("got smthn", got)
 			if !tt.wantErr(t, err, fmt.Sprintf("GetArticles(%v)", tt.args.req)) {
 				return
 			}
 			log.Println("check this")
+			// cursors are sealed with a random nonce, where they lead is tested in Test_service_GetArticles_Cursor
+			got.NextCursor, got.PrevCursor, got.SinceCursor = "", "", ""
 			assert.Equalf(t, tt.want, got, "GetArticles(%v)", tt.args.req)
 		})
 	}
 }
+
+func Test_service_GetArticles_InvalidCategoryMatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ms := store.NewMockStorer(ctrl)
+	s := service.NewService(ms, cursorSecret)
+	ms.EXPECT().GetRecordsAfterID(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	_, err := s.GetArticles(context.Background(), models.GetArticlesRequest{Categories: []string{"uk"}, CategoryMatch: "some"})
+	assert.ErrorIs(t, err, service.ErrInvalidCategoryMatch)
+}
+
+func Test_service_GetArticles_InvalidSort(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ms := store.NewMockStorer(ctrl)
+	s := service.NewService(ms, cursorSecret)
+	ms.EXPECT().GetRecordsAfterID(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	_, err := s.GetArticles(context.Background(), models.GetArticlesRequest{Sort: "popular"})
+	assert.ErrorIs(t, err, service.ErrInvalidSort)
+}
+
+func Test_service_GetArticles_RelevanceNeedsQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ms := store.NewMockStorer(ctrl)
+	s := service.NewService(ms, cursorSecret)
+	ms.EXPECT().GetRecordsAfterID(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	_, err := s.GetArticles(context.Background(), models.GetArticlesRequest{Sort: models.SortRelevance})
+	assert.ErrorIs(t, err, service.ErrRelevanceNeedsQuery)
+	_, err = s.GetArticles(context.Background(), models.GetArticlesRequest{Sort: models.SortRelevance, Query: "provider:sky"})
+	assert.ErrorIs(t, err, service.ErrRelevanceNeedsQuery, "there are no words to rank by")
+}
+
+func Test_service_GetArticles_InvalidQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ms := store.NewMockStorer(ctrl)
+	s := service.NewService(ms, cursorSecret)
+	ms.EXPECT().GetRecordsAfterID(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	_, err := s.GetArticles(context.Background(), models.GetArticlesRequest{Query: "(inflation OR rates"})
+	assert.ErrorIs(t, err, service.ErrInvalidQuery)
+	assert.EqualError(t, err, "invalid query at character 1, the ( is never closed")
+}
+
+func Test_service_GetArticles_FuzzyFallback(t *testing.T) {
+	condition := &store.Condition{Kind: store.ConditionText, Value: "goverment"}
+	exact := store.Filters{Sort: store.SortRelevance, Condition: condition}
+	fuzzy := store.Filters{Sort: store.SortRelevance, Condition: condition, Fuzzy: true}
+
+	t.Run("searches for similar words when nothing matches exactly and keeps doing so for the next page", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := store.NewMockStorer(ctrl)
+		s := service.NewService(ms, cursorSecret)
+		gomock.InOrder(
+			ms.EXPECT().GetRecordsAfterID(gomock.Any(), 0, 4, exact).Return(nil, nil),
+			ms.EXPECT().GetRecordsAfterID(gomock.Any(), 0, 4, fuzzy).Return([]store.NewsArticle{
+				{ID: 1, Title: "Governor"}, {ID: 2, Title: "Governed"}, {ID: 4, Title: "Government"}, {ID: 6, Title: "Govern"},
+			}, nil),
+			ms.EXPECT().GetRecordsAfterID(gomock.Any(), 4, 4, fuzzy).Return([]store.NewsArticle{{ID: 9, Title: "Governments"}}, nil),
+		)
+
+		got, err := s.GetArticles(context.Background(), models.GetArticlesRequest{Query: "goverment"})
+		assert.NoError(t, err)
+		assert.True(t, got.Fuzzy)
+		assert.Equal(t, []models.Article{{ID: 1, Title: "Governor"}, {ID: 2, Title: "Governed"}, {ID: 4, Title: "Government"}}, got.Articles)
+
+		got, err = s.GetArticles(context.Background(), models.GetArticlesRequest{Query: "goverment", Cursor: got.NextCursor})
+		assert.NoError(t, err)
+		assert.True(t, got.Fuzzy)
+		assert.Equal(t, []models.Article{{ID: 9, Title: "Governments"}}, got.Articles)
+	})
+
+	t.Run("doesn't fall back after the first page", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := store.NewMockStorer(ctrl)
+		s := service.NewService(ms, cursorSecret)
+		ms.EXPECT().GetRecordsAfterID(gomock.Any(), 4, 4, exact).Return(nil, nil)
+
+		req := models.GetArticlesRequest{Query: "goverment"}
+		req.Cursor = issueCursor(t, req, 4)
+		_, err := s.GetArticles(context.Background(), req)
+		assert.ErrorIs(t, err, service.ErrNotFound)
+	})
+
+	t.Run("doesn't fall back without a query", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := store.NewMockStorer(ctrl)
+		s := service.NewService(ms, cursorSecret)
+		ms.EXPECT().GetRecordsAfterID(gomock.Any(), 0, 4, store.Filters{Provider: "sky", Descending: true}).Return(nil, nil)
+
+		_, err := s.GetArticles(context.Background(), models.GetArticlesRequest{Provider: "sky"})
+		assert.ErrorIs(t, err, service.ErrNotFound)
+	})
+}
+
+func Test_service_GetArticles_Cursor(t *testing.T) {
+	published := time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC)
+	req := models.GetArticlesRequest{
+		Categories:     []string{"uk", "Politics"},
+		CategoryMatch:  models.CategoryMatchAll,
+		Query:          "election",
+		Sort:           models.SortPublished,
+		PublishedAfter: &published,
+	}
+	filters := store.Filters{
+		Categories:         []string{"uk", "politics"},
+		MatchAllCategories: true,
+		PublishedAfter:     &published,
+		Sort:               store.SortPublished,
+		Descending:         true,
+		Condition:          &store.Condition{Kind: store.ConditionText, Value: "election"},
+	}
+
+	t.Run("continues from the last article of the page", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := store.NewMockStorer(ctrl)
+		s := service.NewService(ms, cursorSecret)
+		gomock.InOrder(
+			ms.EXPECT().GetRecordsAfterID(gomock.Any(), 0, 4, filters).Return([]store.NewsArticle{{ID: 3}, {ID: 5}, {ID: 8}, {ID: 10}}, nil),
+			ms.EXPECT().GetRecordsAfterID(gomock.Any(), 8, 4, filters).Return([]store.NewsArticle{{ID: 12}}, nil),
+		)
+
+		first, err := s.GetArticles(context.Background(), req)
+		require.NoError(t, err)
+		assert.Len(t, first.Articles, 3)
+		assert.Empty(t, first.PrevCursor)
+		next := req
+		next.Cursor = first.NextCursor
+		second, err := s.GetArticles(context.Background(), next)
+		require.NoError(t, err)
+		assert.Empty(t, second.NextCursor, "it's the last page")
+		assert.NotEmpty(t, second.PrevCursor)
+	})
+
+	t.Run("accepts the same filters written differently", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := store.NewMockStorer(ctrl)
+		s := service.NewService(ms, cursorSecret)
+		ms.EXPECT().GetRecordsAfterID(gomock.Any(), 8, 4, gomock.Any()).Return([]store.NewsArticle{{ID: 12}}, nil)
+
+		same := req
+		same.Categories = []string{"POLITICS"}
+		same.Category = "uk"
+		same.Query = " election "
+		inLondon := published.In(time.FixedZone("", 0))
+		same.PublishedAfter = &inLondon
+		same.Cursor = issueCursor(t, req, 8)
+		_, err := s.GetArticles(context.Background(), same)
+		assert.NoError(t, err)
+	})
+
+	ctrl := gomock.NewController(t)
+	other := store.NewMockStorer(ctrl)
+	other.EXPECT().GetRecordsAfterID(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]store.NewsArticle{{ID: 3}, {ID: 5}, {ID: 8}, {ID: 10}}, nil)
+	foreign, err := service.NewService(other, []byte("another secret")).GetArticles(context.Background(), req)
+	require.NoError(t, err)
+
+	rejected := []struct {
+		name   string
+		change func(req *models.GetArticlesRequest)
+		err    error
+	}{
+		{name: "a different query", change: func(req *models.GetArticlesRequest) { req.Query = "elections" }, err: service.ErrCursorMismatch},
+		{name: "a different category", change: func(req *models.GetArticlesRequest) { req.Categories = []string{"uk"} }, err: service.ErrCursorMismatch},
+		{name: "matching any category", change: func(req *models.GetArticlesRequest) { req.CategoryMatch = "" }, err: service.ErrCursorMismatch},
+		{name: "a different sort", change: func(req *models.GetArticlesRequest) { req.Sort = models.SortRelevance }, err: service.ErrCursorMismatch},
+		{name: "no published after", change: func(req *models.GetArticlesRequest) { req.PublishedAfter = nil }, err: service.ErrCursorMismatch},
+		{name: "collapsing clusters", change: func(req *models.GetArticlesRequest) { req.CollapseClusters = true }, err: service.ErrCursorMismatch},
+		{name: "a forged cursor", change: func(req *models.GetArticlesRequest) { req.Cursor = "8" }, err: service.ErrInvalidCursor},
+		{name: "a changed cursor", change: func(req *models.GetArticlesRequest) {
+			changed := []byte(req.Cursor)
+			changed[len(changed)/2] ^= 1
+			req.Cursor = string(changed)
+		}, err: service.ErrInvalidCursor},
+		{name: "a cursor from a service with another secret", change: func(req *models.GetArticlesRequest) {
+			req.Cursor = foreign.NextCursor
+		}, err: service.ErrInvalidCursor},
+	}
+	for _, tt := range rejected {
+		t.Run("rejects "+tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			ms := store.NewMockStorer(ctrl)
+			s := service.NewService(ms, cursorSecret)
+			ms.EXPECT().GetRecordsAfterID(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+			changed := req
+			changed.Cursor = issueCursor(t, req, 8)
+			tt.change(&changed)
+			_, err := s.GetArticles(context.Background(), changed)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func Test_service_GetArticles_Sort(t *testing.T) {
+	tests := []struct {
+		sort    string
+		query   string
+		filters store.Filters
+	}{
+		{sort: "", filters: store.Filters{Descending: true}},
+		{sort: "", query: "provider:sky", filters: store.Filters{
+			Descending: true,
+			Condition:  &store.Condition{Kind: store.ConditionProvider, Value: "sky"},
+		}},
+		{sort: models.SortNewest, filters: store.Filters{Descending: true}},
+		{sort: models.SortOldest, filters: store.Filters{}},
+		{sort: models.SortIngested, filters: store.Filters{}},
+		{sort: models.SortPublished, filters: store.Filters{Sort: store.SortPublished, Descending: true}},
+		{sort: models.SortOldestPublished, filters: store.Filters{Sort: store.SortPublished}},
+		{sort: models.SortRelevance, query: "storm", filters: store.Filters{
+			Sort:      store.SortRelevance,
+			Condition: &store.Condition{Kind: store.ConditionText, Value: "storm"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort+" "+tt.query, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			ms := store.NewMockStorer(ctrl)
+			s := service.NewService(ms, cursorSecret)
+			ms.EXPECT().GetRecordsAfterID(gomock.Any(), 0, 4, tt.filters).Return([]store.NewsArticle{{ID: 1}}, nil)
+
+			_, err := s.GetArticles(context.Background(), models.GetArticlesRequest{Sort: tt.sort, Query: tt.query})
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("ingested and oldest are the same sort for a cursor", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := store.NewMockStorer(ctrl)
+		s := service.NewService(ms, cursorSecret)
+		ms.EXPECT().GetRecordsAfterID(gomock.Any(), 8, 4, store.Filters{}).Return([]store.NewsArticle{{ID: 12}}, nil)
+
+		req := models.GetArticlesRequest{Sort: models.SortOldest}
+		req.Cursor = issueCursor(t, models.GetArticlesRequest{Sort: models.SortIngested}, 8)
+		_, err := s.GetArticles(context.Background(), req)
+		assert.NoError(t, err)
+	})
+}
+
+func Test_service_GetArticles_Pages(t *testing.T) {
+	newest := store.Filters{Descending: true}
+	backwards := store.Filters{Descending: true, Before: true}
+	page := func(ids ...uint) []store.NewsArticle {
+		var articles []store.NewsArticle
+		for _, id := range ids {
+			articles = append(articles, store.NewsArticle{ID: id})
+		}
+		return articles
+	}
+	articleIDs := func(resp models.GetArticlesResponse) []int {
+		var ids []int
+		for _, article := range resp.Articles {
+			ids = append(ids, article.ID)
+		}
+		return ids
+	}
+
+	t.Run("goes forwards and back", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := store.NewMockStorer(ctrl)
+		s := service.NewService(ms, cursorSecret)
+		gomock.InOrder(
+			ms.EXPECT().GetRecordsAfterID(gomock.Any(), 0, 4, newest).Return(page(20, 19, 18, 17), nil),
+			ms.EXPECT().GetRecordsAfterID(gomock.Any(), 18, 4, newest).Return(page(17, 16, 15, 14), nil),
+			ms.EXPECT().GetRecordsAfterID(gomock.Any(), 15, 4, newest).Return(page(14), nil),
+			ms.EXPECT().GetRecordsAfterID(gomock.Any(), 14, 4, backwards).Return(page(18, 17, 16, 15), nil),
+			ms.EXPECT().GetRecordsAfterID(gomock.Any(), 17, 4, backwards).Return(page(20, 19, 18), nil),
+		)
+
+		first, err := s.GetArticles(context.Background(), models.GetArticlesRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, []int{20, 19, 18}, articleIDs(first))
+		assert.Empty(t, first.PrevCursor)
+		assert.NotEmpty(t, first.SinceCursor, "it's the top of the newest articles")
+
+		second, err := s.GetArticles(context.Background(), models.GetArticlesRequest{Cursor: first.NextCursor})
+		require.NoError(t, err)
+		assert.Equal(t, []int{17, 16, 15}, articleIDs(second))
+		assert.NotEmpty(t, second.PrevCursor)
+		assert.Empty(t, second.SinceCursor)
+
+		last, err := s.GetArticles(context.Background(), models.GetArticlesRequest{Cursor: second.NextCursor})
+		require.NoError(t, err)
+		assert.Equal(t, []int{14}, articleIDs(last))
+		assert.Empty(t, last.NextCursor)
+
+		back, err := s.GetArticles(context.Background(), models.GetArticlesRequest{Cursor: last.PrevCursor})
+		require.NoError(t, err)
+		assert.Equal(t, []int{17, 16, 15}, articleIDs(back), "the one furthest from the cursor is dropped")
+		assert.NotEmpty(t, back.NextCursor)
+		assert.NotEmpty(t, back.PrevCursor)
+		assert.Empty(t, back.SinceCursor)
+
+		top, err := s.GetArticles(context.Background(), models.GetArticlesRequest{Cursor: back.PrevCursor})
+		require.NoError(t, err)
+		assert.Equal(t, []int{20, 19, 18}, articleIDs(top))
+		assert.Empty(t, top.PrevCursor)
+		assert.NotEmpty(t, top.SinceCursor, "it's back at the top")
+	})
+
+	t.Run("returns the articles since the client's head", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := store.NewMockStorer(ctrl)
+		s := service.NewService(ms, cursorSecret)
+		gomock.InOrder(
+			ms.EXPECT().GetRecordsAfterID(gomock.Any(), 0, 4, newest).Return(page(20, 19), nil),
+			ms.EXPECT().GetRecordsAfterID(gomock.Any(), 20, 4, backwards).Return(nil, nil),
+			ms.EXPECT().GetRecordsAfterID(gomock.Any(), 20, 4, backwards).Return(page(25, 24, 23, 22), nil),
+			ms.EXPECT().GetRecordsAfterID(gomock.Any(), 24, 4, backwards).Return(page(26, 25), nil),
+		)
+
+		head, err := s.GetArticles(context.Background(), models.GetArticlesRequest{})
+		require.NoError(t, err)
+
+		unchanged, err := s.GetArticles(context.Background(), models.GetArticlesRequest{Cursor: head.SinceCursor})
+		assert.NoError(t, err, "nothing new isn't an error")
+		assert.Empty(t, unchanged.Articles)
+		assert.Equal(t, head.SinceCursor, unchanged.SinceCursor)
+
+		newer, err := s.GetArticles(context.Background(), models.GetArticlesRequest{Cursor: unchanged.SinceCursor})
+		require.NoError(t, err)
+		assert.Equal(t, []int{24, 23, 22}, articleIDs(newer), "the closest to the head come first")
+		assert.Empty(t, newer.NextCursor)
+		assert.Empty(t, newer.PrevCursor)
+
+		rest, err := s.GetArticles(context.Background(), models.GetArticlesRequest{Cursor: newer.SinceCursor})
+		require.NoError(t, err)
+		assert.Equal(t, []int{26, 25}, articleIDs(rest))
+		assert.NotEmpty(t, rest.SinceCursor)
+	})
+
+	t.Run("rejects a since cursor for other filters", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := store.NewMockStorer(ctrl)
+		s := service.NewService(ms, cursorSecret)
+		ms.EXPECT().GetRecordsAfterID(gomock.Any(), 0, 4, newest).Return(page(20), nil)
+
+		head, err := s.GetArticles(context.Background(), models.GetArticlesRequest{})
+		require.NoError(t, err)
+		_, err = s.GetArticles(context.Background(), models.GetArticlesRequest{Cursor: head.SinceCursor, Provider: "sky"})
+		assert.ErrorIs(t, err, service.ErrCursorMismatch)
+	})
+
+	t.Run("only the newest articles have a since cursor", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := store.NewMockStorer(ctrl)
+		s := service.NewService(ms, cursorSecret)
+		ms.EXPECT().GetRecordsAfterID(gomock.Any(), 0, 4, store.Filters{}).Return(page(1, 2), nil)
+
+		got, err := s.GetArticles(context.Background(), models.GetArticlesRequest{Sort: models.SortOldest})
+		require.NoError(t, err)
+		assert.Empty(t, got.SinceCursor)
+	})
+}
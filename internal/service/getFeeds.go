@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/moynur/news-app/internal/models"
+	"github.com/moynur/news-app/internal/store"
+)
+
+var (
+	ErrFeedNotFound = errors.New("no feed found with that id")
+)
+
+// GetFeeds returns the status of every feed, including the ones which have never been fetched
+func (s *service) GetFeeds(ctx context.Context) ([]models.FeedStatus, error) {
+	feeds, err := s.store.GetFeeds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	states, err := s.store.GetFeedStates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]store.FeedState, len(states))
+	for _, state := range states {
+		byName[state.Name] = state
+	}
+	statuses := make([]models.FeedStatus, 0, len(feeds))
+	for _, feed := range feeds {
+		statuses = append(statuses, feedStatus(feed, byName[feed.Name]))
+	}
+	return statuses, nil
+}
+
+// GetFeedStatus returns the status of a single feed, feeds are identified by their name
+func (s *service) GetFeedStatus(ctx context.Context, id string) (models.FeedStatus, error) {
+	feed, err := s.store.GetFeed(ctx, id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return models.FeedStatus{}, ErrFeedNotFound
+		}
+		return models.FeedStatus{}, err
+	}
+	state, err := s.store.GetFeedState(ctx, feed.Name)
+	if err != nil {
+		return models.FeedStatus{}, err
+	}
+	return feedStatus(feed, state), nil
+}
+
+func feedStatus(feed store.Feed, state store.FeedState) models.FeedStatus {
+	status := models.FeedStatus{
+		Name:                feed.Name,
+		URL:                 feed.URL,
+		Enabled:             feed.Enabled,
+		Paused:              state.Paused,
+		LastFetchedAt:       state.LastFetchedAt,
+		LastSuccessAt:       state.LastSuccessAt,
+		LastStatus:          state.LastStatus,
+		ItemsSeen:           state.LastItemsSeen,
+		ItemsInserted:       state.LastItemsInserted,
+		ItemsUpdated:        state.LastItemsUpdated,
+		DuplicatesSkipped:   state.LastDuplicatesSkipped,
+		ConsecutiveFailures: state.ConsecutiveFailures,
+		LastError:           state.LastError,
+	}
+	// disabled and paused feeds aren't going to be fetched again until someone steps in
+	if feed.Enabled && !state.Paused {
+		status.NextRunAt = state.NextFetchAt
+	}
+	return status
+}
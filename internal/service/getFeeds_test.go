@@ -0,0 +1,103 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moynur/news-app/internal/models"
+	"github.com/moynur/news-app/internal/service"
+	"github.com/moynur/news-app/internal/store"
+)
+
+func Test_service_GetFeeds(t *testing.T) {
+	fetched := time.Now()
+	next := fetched.Add(time.Minute)
+	ctrl := gomock.NewController(t)
+	ms := store.NewMockStorer(ctrl)
+	s := service.NewService(ms, cursorSecret)
+	ms.EXPECT().GetFeeds(gomock.Any()).Return([]store.Feed{
+		{Name: "sky-news-uk", URL: "http://sky/uk.xml", Enabled: true},
+		{Name: "sky-news-world", URL: "http://sky/world.xml", Enabled: true},
+		{Name: "sky-news-us", URL: "http://sky/us.xml", Enabled: false},
+	}, nil)
+	ms.EXPECT().GetFeedStates(gomock.Any()).Return([]store.FeedState{
+		{
+			Name:                  "sky-news-uk",
+			LastFetchedAt:         &fetched,
+			NextFetchAt:           &next,
+			LastSuccessAt:         &fetched,
+			LastStatus:            200,
+			LastItemsSeen:         10,
+			LastItemsInserted:     2,
+			LastDuplicatesSkipped: 8,
+		},
+		{
+			Name:                "sky-news-world",
+			LastFetchedAt:       &fetched,
+			NextFetchAt:         &next,
+			ConsecutiveFailures: 10,
+			LastError:           "unexpected status fetching feed 500",
+			LastStatus:          500,
+			Paused:              true,
+		},
+	}, nil)
+
+	got, err := s.GetFeeds(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []models.FeedStatus{
+		{
+			Name:              "sky-news-uk",
+			URL:               "http://sky/uk.xml",
+			Enabled:           true,
+			LastFetchedAt:     &fetched,
+			LastSuccessAt:     &fetched,
+			LastStatus:        200,
+			ItemsSeen:         10,
+			ItemsInserted:     2,
+			DuplicatesSkipped: 8,
+			NextRunAt:         &next,
+		},
+		{
+			Name:                "sky-news-world",
+			URL:                 "http://sky/world.xml",
+			Enabled:             true,
+			Paused:              true,
+			LastFetchedAt:       &fetched,
+			LastStatus:          500,
+			ConsecutiveFailures: 10,
+			LastError:           "unexpected status fetching feed 500",
+		},
+		{
+			Name: "sky-news-us",
+			URL:  "http://sky/us.xml",
+		},
+	}, got)
+}
+
+func Test_service_GetFeedStatus(t *testing.T) {
+	t.Run("returns the status of the feed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := store.NewMockStorer(ctrl)
+		s := service.NewService(ms, cursorSecret)
+		ms.EXPECT().GetFeed(gomock.Any(), "sky-news-uk").Return(store.Feed{Name: "sky-news-uk", URL: "http://sky/uk.xml", Enabled: true}, nil)
+		ms.EXPECT().GetFeedState(gomock.Any(), "sky-news-uk").Return(store.FeedState{Name: "sky-news-uk", LastItemsSeen: 3}, nil)
+
+		got, err := s.GetFeedStatus(context.Background(), "sky-news-uk")
+		assert.NoError(t, err)
+		assert.Equal(t, models.FeedStatus{Name: "sky-news-uk", URL: "http://sky/uk.xml", Enabled: true, ItemsSeen: 3}, got)
+	})
+
+	t.Run("returns not found for an unknown feed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := store.NewMockStorer(ctrl)
+		s := service.NewService(ms, cursorSecret)
+		ms.EXPECT().GetFeed(gomock.Any(), "bbc").Return(store.Feed{}, store.ErrNotFound)
+
+		_, err := s.GetFeedStatus(context.Background(), "bbc")
+		assert.ErrorIs(t, err, service.ErrFeedNotFound)
+	})
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/moynur/news-app/internal/models"
+	"github.com/moynur/news-app/internal/store"
+)
+
+const (
+	defaultRefreshInterval = time.Minute
+	// minRefreshInterval stops a feed being polled so often the publisher rate limits us
+	minRefreshInterval = 30 * time.Second
+)
+
+var (
+	ErrFeedExists  = errors.New("a feed with that id already exists")
+	ErrInvalidFeed = errors.New("invalid feed")
+)
+
+// feedNames have to be safe to use in a URL as they are the feed's id
+var feedNames = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+
+func (s *service) CreateFeed(ctx context.Context, feed models.Feed) (models.Feed, error) {
+	if feed.RefreshInterval == 0 {
+		feed.RefreshInterval = defaultRefreshInterval
+	}
+	err := validateFeed(feed)
+	if err != nil {
+		return models.Feed{}, err
+	}
+	err = s.store.CreateFeed(ctx, toStoreFeed(feed))
+	if err != nil {
+		if errors.Is(err, store.ErrAlreadyExists) {
+			return models.Feed{}, ErrFeedExists
+		}
+		return models.Feed{}, err
+	}
+	return feed, nil
+}
+
+// UpdateFeed changes the feed, enabling a feed also resumes it if it was paused for failing too many times. It is
+// fetched again at its next refresh interval
+func (s *service) UpdateFeed(ctx context.Context, id string, update models.FeedUpdate) (models.Feed, error) {
+	existing, err := s.store.GetFeed(ctx, id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return models.Feed{}, ErrFeedNotFound
+		}
+		return models.Feed{}, err
+	}
+	feed := fromStoreFeed(existing)
+	if update.URL != nil {
+		feed.URL = *update.URL
+	}
+	if update.RefreshInterval != nil {
+		feed.RefreshInterval = *update.RefreshInterval
+	}
+	if update.DefaultCategory != nil {
+		feed.DefaultCategory = *update.DefaultCategory
+	}
+	if update.FallbackImage != nil {
+		feed.FallbackImage = *update.FallbackImage
+	}
+	if update.Enabled != nil {
+		feed.Enabled = *update.Enabled
+	}
+	err = validateFeed(feed)
+	if err != nil {
+		return models.Feed{}, err
+	}
+	err = s.store.UpdateFeed(ctx, toStoreFeed(feed))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return models.Feed{}, ErrFeedNotFound
+		}
+		return models.Feed{}, err
+	}
+	if update.Enabled != nil && *update.Enabled {
+		err = s.store.ResumeFeed(ctx, feed.Name)
+		if err != nil {
+			return models.Feed{}, err
+		}
+	}
+	return feed, nil
+}
+
+// DeleteFeed stops the feed being polled, articles already taken from it are kept
+func (s *service) DeleteFeed(ctx context.Context, id string) error {
+	err := s.store.DeleteFeed(ctx, id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrFeedNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func validateFeed(feed models.Feed) error {
+	if !feedNames.MatchString(feed.Name) {
+		return fmt.Errorf("%w: id must only contain letters, numbers, dots, dashes and underscores", ErrInvalidFeed)
+	}
+	if !isAbsoluteURL(feed.URL) {
+		return fmt.Errorf("%w: url must be an absolute http or https url", ErrInvalidFeed)
+	}
+	if feed.FallbackImage != "" && !isAbsoluteURL(feed.FallbackImage) {
+		return fmt.Errorf("%w: fallback image must be an absolute http or https url", ErrInvalidFeed)
+	}
+	if feed.RefreshInterval < minRefreshInterval {
+		return fmt.Errorf("%w: refresh interval must be at least %s", ErrInvalidFeed, minRefreshInterval)
+	}
+	return nil
+}
+
+func isAbsoluteURL(raw string) bool {
+	parsed, err := url.Parse(raw)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+func toStoreFeed(feed models.Feed) store.Feed {
+	return store.Feed{
+		Name:                   feed.Name,
+		URL:                    feed.URL,
+		RefreshIntervalSeconds: int(feed.RefreshInterval / time.Second),
+		DefaultCategory:        feed.DefaultCategory,
+		FallbackImage:          feed.FallbackImage,
+		Enabled:                feed.Enabled,
+	}
+}
+
+func fromStoreFeed(feed store.Feed) models.Feed {
+	return models.Feed{
+		Name:            feed.Name,
+		URL:             feed.URL,
+		RefreshInterval: time.Duration(feed.RefreshIntervalSeconds) * time.Second,
+		DefaultCategory: feed.DefaultCategory,
+		FallbackImage:   feed.FallbackImage,
+		Enabled:         feed.Enabled,
+	}
+}
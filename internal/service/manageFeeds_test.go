@@ -0,0 +1,106 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moynur/news-app/internal/models"
+	"github.com/moynur/news-app/internal/service"
+	"github.com/moynur/news-app/internal/store"
+)
+
+func Test_service_CreateFeed(t *testing.T) {
+	tests := []struct {
+		name     string
+		feed     models.Feed
+		storeErr error
+		want     models.Feed
+		wantErr  error
+	}{
+		{
+			name: "creates the feed with the default refresh interval",
+			feed: models.Feed{Name: "bbc-news", URL: "https://feeds.bbci.co.uk/news/rss.xml", Enabled: true},
+			want: models.Feed{Name: "bbc-news", URL: "https://feeds.bbci.co.uk/news/rss.xml", RefreshInterval: time.Minute, Enabled: true},
+		},
+		{
+			name:     "returns an error when the feed exists",
+			feed:     models.Feed{Name: "bbc-news", URL: "https://feeds.bbci.co.uk/news/rss.xml"},
+			storeErr: store.ErrAlreadyExists,
+			wantErr:  service.ErrFeedExists,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			ms := store.NewMockStorer(ctrl)
+			s := service.NewService(ms, cursorSecret)
+			ms.EXPECT().CreateFeed(gomock.Any(), gomock.Any()).Return(tt.storeErr)
+
+			got, err := s.CreateFeed(context.Background(), tt.feed)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("rejects an invalid feed", func(t *testing.T) {
+		for _, feed := range []models.Feed{
+			{Name: "bbc news", URL: "https://feeds.bbci.co.uk/news/rss.xml"},
+			{Name: "bbc-news", URL: "feeds.bbci.co.uk/news/rss.xml"},
+			{Name: "bbc-news", URL: "https://feeds.bbci.co.uk/news/rss.xml", RefreshInterval: time.Second},
+			{Name: "bbc-news", URL: "https://feeds.bbci.co.uk/news/rss.xml", FallbackImage: "logo.png"},
+		} {
+			ctrl := gomock.NewController(t)
+			s := service.NewService(store.NewMockStorer(ctrl), cursorSecret)
+			_, err := s.CreateFeed(context.Background(), feed)
+			assert.ErrorIs(t, err, service.ErrInvalidFeed)
+		}
+	})
+}
+
+func Test_service_UpdateFeed(t *testing.T) {
+	existing := store.Feed{Name: "bbc-news", URL: "https://feeds.bbci.co.uk/news/rss.xml", RefreshIntervalSeconds: 60}
+
+	t.Run("enabling a feed resumes it", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := store.NewMockStorer(ctrl)
+		s := service.NewService(ms, cursorSecret)
+		enabled := true
+		interval := 5 * time.Minute
+
+		ms.EXPECT().GetFeed(gomock.Any(), "bbc-news").Return(existing, nil)
+		ms.EXPECT().UpdateFeed(gomock.Any(), store.Feed{
+			Name:                   "bbc-news",
+			URL:                    "https://feeds.bbci.co.uk/news/rss.xml",
+			RefreshIntervalSeconds: 300,
+			Enabled:                true,
+		}).Return(nil)
+		ms.EXPECT().ResumeFeed(gomock.Any(), "bbc-news").Return(nil)
+
+		got, err := s.UpdateFeed(context.Background(), "bbc-news", models.FeedUpdate{Enabled: &enabled, RefreshInterval: &interval})
+		assert.NoError(t, err)
+		assert.Equal(t, models.Feed{Name: "bbc-news", URL: "https://feeds.bbci.co.uk/news/rss.xml", RefreshInterval: interval, Enabled: true}, got)
+	})
+
+	t.Run("returns not found for an unknown feed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := store.NewMockStorer(ctrl)
+		s := service.NewService(ms, cursorSecret)
+		ms.EXPECT().GetFeed(gomock.Any(), "bbc-news").Return(store.Feed{}, store.ErrNotFound)
+
+		_, err := s.UpdateFeed(context.Background(), "bbc-news", models.FeedUpdate{})
+		assert.ErrorIs(t, err, service.ErrFeedNotFound)
+	})
+}
+
+func Test_service_DeleteFeed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ms := store.NewMockStorer(ctrl)
+	s := service.NewService(ms, cursorSecret)
+	ms.EXPECT().DeleteFeed(gomock.Any(), "bbc-news").Return(store.ErrNotFound)
+
+	assert.ErrorIs(t, s.DeleteFeed(context.Background(), "bbc-news"), service.ErrFeedNotFound)
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/moynur/news-app/internal/models"
+	"github.com/moynur/news-app/internal/opml"
+)
+
+var (
+	ErrInvalidOPML = errors.New("invalid opml document")
+)
+
+var notFeedName = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// ImportOPML adds every feed in the OPML document which hasn't already been added, feeds are matched on their url.
+// A feed which can't be added doesn't stop the rest of the import, it is reported as failed instead
+func (s *service) ImportOPML(ctx context.Context, r io.Reader) (models.FeedImport, error) {
+	var result models.FeedImport
+	subscriptions, err := opml.Parse(r)
+	if err != nil {
+		return result, fmt.Errorf("%w: %s", ErrInvalidOPML, err.Error())
+	}
+	existing, err := s.store.GetFeeds(ctx)
+	if err != nil {
+		return result, err
+	}
+	urls := map[string]bool{}
+	names := map[string]bool{}
+	for _, feed := range existing {
+		urls[feed.URL] = true
+		names[feed.Name] = true
+	}
+
+	for _, subscription := range subscriptions {
+		if urls[subscription.XMLURL] {
+			result.Skipped = append(result.Skipped, subscription.XMLURL)
+			continue
+		}
+		feed := models.Feed{
+			Name:    uniqueFeedName(subscription, names),
+			URL:     subscription.XMLURL,
+			Enabled: true,
+		}
+		if len(subscription.Categories) > 0 {
+			feed.DefaultCategory = subscription.Categories[0]
+		}
+		feed, err = s.CreateFeed(ctx, feed)
+		switch {
+		case errors.Is(err, ErrInvalidFeed) || errors.Is(err, ErrFeedExists):
+			result.Failed = append(result.Failed, models.FeedImportFailure{URL: subscription.XMLURL, Reason: err.Error()})
+		case err != nil:
+			return result, err
+		default:
+			urls[feed.URL] = true
+			names[feed.Name] = true
+			result.Created = append(result.Created, feed)
+		}
+	}
+	return result, nil
+}
+
+// ExportOPML writes every feed as an OPML document, the feed's default category is used as its folder
+func (s *service) ExportOPML(ctx context.Context, w io.Writer) error {
+	feeds, err := s.store.GetFeeds(ctx)
+	if err != nil {
+		return err
+	}
+	subscriptions := make([]opml.Subscription, 0, len(feeds))
+	for _, feed := range feeds {
+		subscription := opml.Subscription{
+			Title:  feed.Name,
+			XMLURL: feed.URL,
+		}
+		if feed.DefaultCategory != "" {
+			subscription.Categories = []string{feed.DefaultCategory}
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return opml.Write(w, "news-app feeds", subscriptions)
+}
+
+// uniqueFeedName makes an id from the subscription's title, or its host when it has no title, adding a number to the
+// end when the id has already been taken
+func uniqueFeedName(subscription opml.Subscription, taken map[string]bool) string {
+	name := subscription.Title
+	if parsed, err := url.Parse(subscription.XMLURL); name == "" && err == nil {
+		name = parsed.Host
+	}
+	name = strings.Trim(notFeedName.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if name == "" {
+		name = "feed"
+	}
+	unique := name
+	for i := 2; taken[unique]; i++ {
+		unique = fmt.Sprintf("%s-%d", name, i)
+	}
+	return unique
+}
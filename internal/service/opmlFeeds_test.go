@@ -0,0 +1,81 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moynur/news-app/internal/models"
+	"github.com/moynur/news-app/internal/service"
+	"github.com/moynur/news-app/internal/store"
+)
+
+const subscriptions = `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+	<body>
+		<outline text="World">
+			<outline text="Sky News" type="rss" xmlUrl="http://feeds.skynews.com/feeds/rss/world.xml"/>
+		</outline>
+		<outline text="Sky News UK" type="rss" xmlUrl="http://feeds.skynews.com/feeds/rss/uk.xml"/>
+		<outline text="Broken" type="rss" xmlUrl="ftp://example.com/feed.xml"/>
+	</body>
+</opml>`
+
+func Test_service_ImportOPML(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ms := store.NewMockStorer(ctrl)
+	s := service.NewService(ms, cursorSecret)
+
+	ms.EXPECT().GetFeeds(gomock.Any()).Return([]store.Feed{
+		{Name: "sky-news", URL: "http://feeds.skynews.com/feeds/rss/home.xml"},
+		{Name: "sky-news-uk", URL: "http://feeds.skynews.com/feeds/rss/uk.xml"},
+	}, nil)
+	// the id is taken from the title, with a number added as sky-news is already used
+	ms.EXPECT().CreateFeed(gomock.Any(), store.Feed{
+		Name:                   "sky-news-2",
+		URL:                    "http://feeds.skynews.com/feeds/rss/world.xml",
+		RefreshIntervalSeconds: 60,
+		DefaultCategory:        "World",
+		Enabled:                true,
+	}).Return(nil)
+
+	got, err := s.ImportOPML(context.Background(), strings.NewReader(subscriptions))
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Feed{{
+		Name:            "sky-news-2",
+		URL:             "http://feeds.skynews.com/feeds/rss/world.xml",
+		RefreshInterval: time.Minute,
+		DefaultCategory: "World",
+		Enabled:         true,
+	}}, got.Created)
+	assert.Equal(t, []string{"http://feeds.skynews.com/feeds/rss/uk.xml"}, got.Skipped)
+	assert.Len(t, got.Failed, 1)
+	assert.Equal(t, "ftp://example.com/feed.xml", got.Failed[0].URL)
+}
+
+func Test_service_ImportOPML_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	s := service.NewService(store.NewMockStorer(ctrl), cursorSecret)
+
+	_, err := s.ImportOPML(context.Background(), strings.NewReader("not opml"))
+	assert.ErrorIs(t, err, service.ErrInvalidOPML)
+}
+
+func Test_service_ExportOPML(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ms := store.NewMockStorer(ctrl)
+	s := service.NewService(ms, cursorSecret)
+
+	ms.EXPECT().GetFeeds(gomock.Any()).Return([]store.Feed{
+		{Name: "sky-news-uk", URL: "http://feeds.skynews.com/feeds/rss/uk.xml", DefaultCategory: "uk"},
+	}, nil)
+
+	var buf bytes.Buffer
+	assert.NoError(t, s.ExportOPML(context.Background(), &buf))
+	assert.Contains(t, buf.String(), `<outline text="sky-news-uk" title="sky-news-uk" type="rss" xmlUrl="http://feeds.skynews.com/feeds/rss/uk.xml" category="uk">`)
+}
@@ -0,0 +1,387 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/moynur/news-app/internal/store"
+)
+
+const (
+	// maxQueryLength and maxQueryTerms keep a query from turning into more SQL than the database should have to run
+	maxQueryLength = 500
+	maxQueryTerms  = 32
+)
+
+var ErrInvalidQuery = errors.New("invalid query")
+
+// QueryError is why a search query couldn't be parsed, Position is the character it went wrong at counting from 1
+type QueryError struct {
+	Position int
+	Reason   string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("invalid query at character %d, %s", e.Position, e.Reason)
+}
+
+func (e *QueryError) Unwrap() error {
+	return ErrInvalidQuery
+}
+
+// queryFields are the fields a term can be limited to, as in provider:sky
+var queryFields = map[string]store.ConditionKind{
+	"title":    store.ConditionTitle,
+	"summary":  store.ConditionDescription,
+	"provider": store.ConditionProvider,
+	"category": store.ConditionCategory,
+	"after":    store.ConditionPublishedAfter,
+	"before":   store.ConditionPublishedBefore,
+}
+
+var fieldName = regexp.MustCompile(`^[A-Za-z]+$`)
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenPhrase
+	tokenField
+	tokenOpen
+	tokenClose
+	tokenNot
+	tokenAnd
+	tokenOr
+	tokenEnd
+)
+
+type token struct {
+	kind tokenKind
+	// field is only set for a field token, value is the word, the phrase or the field's value
+	field string
+	value string
+	// position is the character the token starts at counting from 1
+	position int
+}
+
+// ParseQuery turns a search query into a condition, it returns nil when the query has nothing to search for.
+//
+// Words have to all be in the article, "quoted phrases" have to be in it as they are and a term can be limited to a
+// field with title:, summary:, provider: or category:. after: and before: take a date such as 2026-01-01 and compare it
+// with when the article was published. Terms are combined with AND, which is the same as leaving it out, and OR, and
+// are negated with NOT or a leading -. AND is applied before OR and brackets group terms, for example
+//
+//	title:"bank of england" AND (inflation OR rates) -sport provider:sky after:2026-01-01
+func ParseQuery(query string) (*store.Condition, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, nil
+	}
+	if len([]rune(query)) > maxQueryLength {
+		return nil, &QueryError{Position: maxQueryLength + 1, Reason: fmt.Sprintf("the query is longer than %d characters", maxQueryLength)}
+	}
+	tokens, err := lexQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	p := parser{tokens: tokens}
+	condition, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != tokenEnd {
+		// parseOr only stops early at a bracket it didn't open
+		return nil, &QueryError{Position: next.position, Reason: "there's a ) without a ( before it"}
+	}
+	if p.terms > maxQueryTerms {
+		return nil, &QueryError{Position: 1, Reason: fmt.Sprintf("the query has more than %d terms", maxQueryTerms)}
+	}
+	return condition, nil
+}
+
+// lexQuery splits the query into tokens, the last one is always tokenEnd
+func lexQuery(query string) ([]token, error) {
+	runes := []rune(query)
+	var tokens []token
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i + 1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenOpen, position: start})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenClose, position: start})
+			i++
+		case r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) && runes[i+1] != ')':
+			tokens = append(tokens, token{kind: tokenNot, position: start})
+			i++
+		case r == '"':
+			phrase, next, err := lexQuoted(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenPhrase, value: phrase, position: start})
+			i = next
+		default:
+			next := i
+			for next < len(runes) && !unicode.IsSpace(runes[next]) && !strings.ContainsRune(`()"`, runes[next]) {
+				next++
+			}
+			word := string(runes[i:next])
+			tok, err := wordToken(word, start)
+			if err != nil {
+				return nil, err
+			}
+			// a field's value can be quoted, as in title:"bank of england"
+			if tok.kind == tokenField && tok.value == "" && next < len(runes) && runes[next] == '"' {
+				tok.value, next, err = lexQuoted(runes, next)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if tok.kind == tokenField && strings.TrimSpace(tok.value) == "" {
+				return nil, &QueryError{Position: start, Reason: fmt.Sprintf("%s: needs a value after it", tok.field)}
+			}
+			tokens = append(tokens, tok)
+			i = next
+		}
+	}
+	return append(tokens, token{kind: tokenEnd, position: len(runes) + 1}), nil
+}
+
+// lexQuoted reads the quoted text starting at the quote at i, a backslash escapes a quote or another backslash. It
+// returns the text and where the token after it starts
+func lexQuoted(runes []rune, i int) (string, int, error) {
+	var text strings.Builder
+	for next := i + 1; next < len(runes); next++ {
+		switch {
+		case runes[next] == '\\' && next+1 < len(runes) && (runes[next+1] == '"' || runes[next+1] == '\\'):
+			next++
+			text.WriteRune(runes[next])
+		case runes[next] == '"':
+			return text.String(), next + 1, nil
+		default:
+			text.WriteRune(runes[next])
+		}
+	}
+	return "", 0, &QueryError{Position: i + 1, Reason: "the quote is never closed"}
+}
+
+func wordToken(word string, position int) (token, error) {
+	switch word {
+	case "AND":
+		return token{kind: tokenAnd, position: position}, nil
+	case "OR":
+		return token{kind: tokenOr, position: position}, nil
+	case "NOT":
+		return token{kind: tokenNot, position: position}, nil
+	}
+	colon := strings.IndexRune(word, ':')
+	// anything else with a colon in it, like 10:30, is searched for as words
+	if colon <= 0 || !fieldName.MatchString(word[:colon]) {
+		return token{kind: tokenWord, value: word, position: position}, nil
+	}
+	field := strings.ToLower(word[:colon])
+	if _, ok := queryFields[field]; !ok {
+		return token{}, &QueryError{Position: position, Reason: fmt.Sprintf("%s isn't a field, use title, summary, provider, category, after or before", field)}
+	}
+	return token{kind: tokenField, field: field, value: word[colon+1:], position: position}, nil
+}
+
+// parser is a recursive descent parser over the tokens, the conditions it returns are nil for terms which have nothing
+// to search for such as a lone &
+type parser struct {
+	tokens []token
+	next   int
+	terms  int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) take() token {
+	tok := p.tokens[p.next]
+	if tok.kind != tokenEnd {
+		p.next++
+	}
+	return tok
+}
+
+// parseOr parses terms joined by OR, which is applied after AND
+func (p *parser) parseOr() (*store.Condition, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	conditions := appendCondition(nil, first)
+	for p.peek().kind == tokenOr {
+		or := p.take()
+		if !p.startsTerm() {
+			return nil, &QueryError{Position: or.position, Reason: "OR needs a term after it"}
+		}
+		next, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		conditions = appendCondition(conditions, next)
+	}
+	return combine(store.ConditionAny, conditions), nil
+}
+
+// parseAnd parses terms joined by AND or next to each other
+func (p *parser) parseAnd() (*store.Condition, error) {
+	if !p.startsTerm() {
+		return nil, p.unexpected()
+	}
+	var conditions []store.Condition
+	for {
+		term, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		conditions = appendCondition(conditions, term)
+		if p.peek().kind == tokenAnd {
+			and := p.take()
+			if !p.startsTerm() {
+				return nil, &QueryError{Position: and.position, Reason: "AND needs a term after it"}
+			}
+			continue
+		}
+		if !p.startsTerm() {
+			return combine(store.ConditionAll, mergeText(conditions)), nil
+		}
+	}
+}
+
+// mergeText puts the words of every text condition into the first one as they all have to match anyway, searching for
+// them together lets the database ignore words like "the" which are too common to be indexed
+func mergeText(conditions []store.Condition) []store.Condition {
+	merged := conditions[:0]
+	text := -1
+	for _, condition := range conditions {
+		if condition.Kind == store.ConditionText && text >= 0 {
+			merged[text].Value += " " + condition.Value
+			continue
+		}
+		if condition.Kind == store.ConditionText {
+			text = len(merged)
+		}
+		merged = append(merged, condition)
+	}
+	return merged
+}
+
+// parseUnary parses a term which might be negated
+func (p *parser) parseUnary() (*store.Condition, error) {
+	if p.peek().kind != tokenNot {
+		return p.parsePrimary()
+	}
+	not := p.take()
+	if !p.startsTerm() {
+		return nil, &QueryError{Position: not.position, Reason: "NOT needs a term after it"}
+	}
+	term, err := p.parseUnary()
+	if err != nil || term == nil {
+		return nil, err
+	}
+	return &store.Condition{Kind: store.ConditionNot, Conditions: []store.Condition{*term}}, nil
+}
+
+// parsePrimary parses a word, a phrase, a field or a group in brackets
+func (p *parser) parsePrimary() (*store.Condition, error) {
+	tok := p.take()
+	switch tok.kind {
+	case tokenOpen:
+		if p.peek().kind == tokenClose {
+			return nil, &QueryError{Position: tok.position, Reason: "the brackets are empty"}
+		}
+		group, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.take().kind != tokenClose {
+			return nil, &QueryError{Position: tok.position, Reason: "the ( is never closed"}
+		}
+		return group, nil
+	case tokenWord, tokenPhrase:
+		words := strings.FieldsFunc(tok.value, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		})
+		if len(words) == 0 {
+			return nil, nil
+		}
+		p.terms++
+		kind := store.ConditionText
+		if tok.kind == tokenPhrase && len(words) > 1 {
+			kind = store.ConditionPhrase
+		}
+		return &store.Condition{Kind: kind, Value: strings.Join(words, " ")}, nil
+	case tokenField:
+		p.terms++
+		return fieldCondition(tok)
+	}
+	return nil, &QueryError{Position: tok.position, Reason: "a term was expected"}
+}
+
+func fieldCondition(tok token) (*store.Condition, error) {
+	kind := queryFields[tok.field]
+	if kind != store.ConditionPublishedAfter && kind != store.ConditionPublishedBefore {
+		return &store.Condition{Kind: kind, Value: tok.value}, nil
+	}
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.Parse(layout, tok.value); err == nil {
+			return &store.Condition{Kind: kind, Time: t}, nil
+		}
+	}
+	return nil, &QueryError{Position: tok.position, Reason: fmt.Sprintf("%s: needs a date such as 2026-01-01, not %q", tok.field, tok.value)}
+}
+
+// startsTerm is whether the next token can be the start of a term
+func (p *parser) startsTerm() bool {
+	switch p.peek().kind {
+	case tokenWord, tokenPhrase, tokenField, tokenOpen, tokenNot:
+		return true
+	}
+	return false
+}
+
+// unexpected is the error for a token which can't start a term
+func (p *parser) unexpected() error {
+	tok := p.peek()
+	switch tok.kind {
+	case tokenEnd:
+		return &QueryError{Position: tok.position, Reason: "the query ends before a term"}
+	case tokenClose:
+		return &QueryError{Position: tok.position, Reason: "there's a ) without a ( before it"}
+	case tokenAnd:
+		return &QueryError{Position: tok.position, Reason: "AND needs a term before it"}
+	case tokenOr:
+		return &QueryError{Position: tok.position, Reason: "OR needs a term before it"}
+	}
+	return &QueryError{Position: tok.position, Reason: "a term was expected"}
+}
+
+func appendCondition(conditions []store.Condition, condition *store.Condition) []store.Condition {
+	if condition == nil {
+		return conditions
+	}
+	return append(conditions, *condition)
+}
+
+// combine joins the conditions, one condition is returned as it is and none is nil
+func combine(kind store.ConditionKind, conditions []store.Condition) *store.Condition {
+	switch len(conditions) {
+	case 0:
+		return nil
+	case 1:
+		return &conditions[0]
+	}
+	return &store.Condition{Kind: kind, Conditions: conditions}
+}
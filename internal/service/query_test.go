@@ -0,0 +1,207 @@
+package service_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moynur/news-app/internal/service"
+	"github.com/moynur/news-app/internal/store"
+)
+
+func text(value string) store.Condition {
+	return store.Condition{Kind: store.ConditionText, Value: value}
+}
+
+func allOf(conditions ...store.Condition) store.Condition {
+	return store.Condition{Kind: store.ConditionAll, Conditions: conditions}
+}
+
+func anyOf(conditions ...store.Condition) store.Condition {
+	return store.Condition{Kind: store.ConditionAny, Conditions: conditions}
+}
+
+func not(condition store.Condition) store.Condition {
+	return store.Condition{Kind: store.ConditionNot, Conditions: []store.Condition{condition}}
+}
+
+func TestParseQuery(t *testing.T) {
+	after := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		query string
+		want  *store.Condition
+	}{
+		{
+			name:  "blank",
+			query: "  ",
+			want:  nil,
+		},
+		{
+			name:  "words are searched for together",
+			query: "economy recession",
+			want:  &store.Condition{Kind: store.ConditionText, Value: "economy recession"},
+		},
+		{
+			name:  "punctuation separates words",
+			query: "covid-19 & the economy's",
+			want:  &store.Condition{Kind: store.ConditionText, Value: "covid 19 the economy s"},
+		},
+		{
+			name:  "nothing to search for",
+			query: "& * ?",
+			want:  nil,
+		},
+		{
+			name:  "phrase",
+			query: `"bank of england"`,
+			want:  &store.Condition{Kind: store.ConditionPhrase, Value: "bank of england"},
+		},
+		{
+			name:  "a phrase of one word is a word",
+			query: `"inflation"`,
+			want:  &store.Condition{Kind: store.ConditionText, Value: "inflation"},
+		},
+		{
+			name:  "escaped quotes",
+			query: `title:"the \"big\" \\ one"`,
+			want:  &store.Condition{Kind: store.ConditionTitle, Value: `the "big" \ one`},
+		},
+		{
+			name:  "fields",
+			query: `title:"bank of england" summary:rates provider:sky category:UK`,
+			want: &store.Condition{Kind: store.ConditionAll, Conditions: []store.Condition{
+				{Kind: store.ConditionTitle, Value: "bank of england"},
+				{Kind: store.ConditionDescription, Value: "rates"},
+				{Kind: store.ConditionProvider, Value: "sky"},
+				{Kind: store.ConditionCategory, Value: "UK"},
+			}},
+		},
+		{
+			name:  "field names ignore case and values keep their wildcards",
+			query: "Title:100%_",
+			want:  &store.Condition{Kind: store.ConditionTitle, Value: "100%_"},
+		},
+		{
+			name:  "dates",
+			query: "after:2026-01-01 before:2026-01-31T12:00:00+01:00",
+			want: &store.Condition{Kind: store.ConditionAll, Conditions: []store.Condition{
+				{Kind: store.ConditionPublishedAfter, Time: after},
+				{Kind: store.ConditionPublishedBefore, Time: time.Date(2026, 1, 31, 12, 0, 0, 0, time.FixedZone("", 60*60))},
+			}},
+		},
+		{
+			name:  "a colon which isn't after a field name",
+			query: "10:30 news",
+			want:  &store.Condition{Kind: store.ConditionText, Value: "10 30 news"},
+		},
+		{
+			name:  "the example from the docs",
+			query: `title:"bank of england" AND (inflation OR rates) -sport provider:sky after:2026-01-01`,
+			want: &store.Condition{Kind: store.ConditionAll, Conditions: []store.Condition{
+				{Kind: store.ConditionTitle, Value: "bank of england"},
+				anyOf(text("inflation"), text("rates")),
+				not(text("sport")),
+				{Kind: store.ConditionProvider, Value: "sky"},
+				{Kind: store.ConditionPublishedAfter, Time: after},
+			}},
+		},
+		{
+			name:  "AND before OR",
+			query: "a b OR c AND d OR e",
+			want:  &store.Condition{Kind: store.ConditionAny, Conditions: []store.Condition{text("a b"), text("c d"), text("e")}},
+		},
+		{
+			name:  "brackets",
+			query: "a AND (b OR (c -d))",
+			want: &store.Condition{Kind: store.ConditionAll, Conditions: []store.Condition{
+				text("a"),
+				anyOf(text("b"), allOf(text("c"), not(text("d")))),
+			}},
+		},
+		{
+			name:  "NOT and minus",
+			query: `NOT sport -"bank holiday" -(a OR b) - c`,
+			want: &store.Condition{Kind: store.ConditionAll, Conditions: []store.Condition{
+				not(text("sport")),
+				not(store.Condition{Kind: store.ConditionPhrase, Value: "bank holiday"}),
+				not(anyOf(text("a"), text("b"))),
+				text("c"),
+			}},
+		},
+		{
+			name:  "double negative",
+			query: "NOT -sport",
+			want:  &store.Condition{Kind: store.ConditionNot, Conditions: []store.Condition{not(text("sport"))}},
+		},
+		{
+			name:  "lower case operators are words",
+			query: "fish and chips or peas not",
+			want:  &store.Condition{Kind: store.ConditionText, Value: "fish and chips or peas not"},
+		},
+		{
+			name:  "terms with nothing to search for are dropped",
+			query: "a OR & OR (*) OR -?",
+			want:  &store.Condition{Kind: store.ConditionText, Value: "a"},
+		},
+		{
+			name:  "hyphens inside a word",
+			query: "-covid-19",
+			want:  &store.Condition{Kind: store.ConditionNot, Conditions: []store.Condition{text("covid 19")}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := service.ParseQuery(tt.query)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseQuery_Invalid(t *testing.T) {
+	tests := []struct {
+		query    string
+		position int
+		reason   string
+	}{
+		{query: `title:"bank of england`, position: 7, reason: "the quote is never closed"},
+		{query: `a "b`, position: 3, reason: "the quote is never closed"},
+		{query: "(a OR b", position: 1, reason: "the ( is never closed"},
+		{query: "a (b (c)", position: 3, reason: "the ( is never closed"},
+		{query: "a OR b)", position: 7, reason: "there's a ) without a ( before it"},
+		{query: ")", position: 1, reason: "there's a ) without a ( before it"},
+		{query: "a ()", position: 3, reason: "the brackets are empty"},
+		{query: "OR a", position: 1, reason: "OR needs a term before it"},
+		{query: "a OR", position: 3, reason: "OR needs a term after it"},
+		{query: "a OR OR b", position: 3, reason: "OR needs a term after it"},
+		{query: "AND a", position: 1, reason: "AND needs a term before it"},
+		{query: "a AND", position: 3, reason: "AND needs a term after it"},
+		{query: "a AND OR b", position: 3, reason: "AND needs a term after it"},
+		{query: "a (OR b)", position: 4, reason: "OR needs a term before it"},
+		{query: "a NOT", position: 3, reason: "NOT needs a term after it"},
+		{query: "a -)", position: 4, reason: "there's a ) without a ( before it"},
+		{query: "title: bank", position: 1, reason: "title: needs a value after it"},
+		{query: `a title:""`, position: 3, reason: "title: needs a value after it"},
+		{query: "author:smith", position: 1, reason: "author isn't a field"},
+		{query: "a after:yesterday", position: 3, reason: `after: needs a date such as 2026-01-01, not "yesterday"`},
+		{query: "before:2026-13-01", position: 1, reason: "before: needs a date"},
+		{query: strings.Repeat("a", 501), position: 501, reason: "the query is longer than 500 characters"},
+		{query: strings.Repeat("a OR ", 32) + "a", position: 1, reason: "the query has more than 32 terms"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := service.ParseQuery(tt.query)
+			assert.Nil(t, got)
+			assert.ErrorIs(t, err, service.ErrInvalidQuery)
+			var queryErr *service.QueryError
+			if assert.True(t, errors.As(err, &queryErr), err) {
+				assert.Equal(t, tt.position, queryErr.Position)
+				assert.Contains(t, queryErr.Reason, tt.reason)
+			}
+		})
+	}
+}
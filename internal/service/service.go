@@ -2,21 +2,38 @@
 package service
 
 import (
+	"context"
+	"crypto/cipher"
+	"io"
+
 	"github.com/moynur/news-app/internal/models"
 	"github.com/moynur/news-app/internal/store"
 )
 
 type Service interface {
-	GetArticles(request models.GetArticlesRequest) (models.GetArticlesResponse, error)
+	GetArticles(ctx context.Context, request models.GetArticlesRequest) (models.GetArticlesResponse, error)
+	GetArticleRevisions(ctx context.Context, id int) ([]models.ArticleRevision, error)
+	Suggest(ctx context.Context, prefix string) ([]models.Suggestion, error)
+	GetFeeds(ctx context.Context) ([]models.FeedStatus, error)
+	GetFeedStatus(ctx context.Context, id string) (models.FeedStatus, error)
+	CreateFeed(ctx context.Context, feed models.Feed) (models.Feed, error)
+	UpdateFeed(ctx context.Context, id string, update models.FeedUpdate) (models.Feed, error)
+	DeleteFeed(ctx context.Context, id string) error
+	ImportOPML(ctx context.Context, r io.Reader) (models.FeedImport, error)
+	ExportOPML(ctx context.Context, w io.Writer) error
 }
 
 type service struct {
-	store      store.Storer
-	rssFeedUrl string
+	store store.Storer
+	// cursorAEAD seals the cursors given to clients
+	cursorAEAD cipher.AEAD
 }
 
-func NewService(db store.Storer) *service {
+// NewService uses the cursor secret to seal pagination cursors, every replica needs the same one for a cursor from one
+// to work on another
+func NewService(db store.Storer, cursorSecret []byte) *service {
 	return &service{
-		store: db,
+		store:      db,
+		cursorAEAD: newCursorAEAD(cursorSecret),
 	}
 }
@@ -5,6 +5,8 @@
 package service
 
 import (
+	context "context"
+	io "io"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -34,17 +36,150 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// CreateFeed mocks base method.
+func (m *MockService) CreateFeed(ctx context.Context, feed models.Feed) (models.Feed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFeed", ctx, feed)
+	ret0, _ := ret[0].(models.Feed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFeed indicates an expected call of CreateFeed.
+func (mr *MockServiceMockRecorder) CreateFeed(ctx, feed interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFeed", reflect.TypeOf((*MockService)(nil).CreateFeed), ctx, feed)
+}
+
+// DeleteFeed mocks base method.
+func (m *MockService) DeleteFeed(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFeed", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFeed indicates an expected call of DeleteFeed.
+func (mr *MockServiceMockRecorder) DeleteFeed(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFeed", reflect.TypeOf((*MockService)(nil).DeleteFeed), ctx, id)
+}
+
+// ExportOPML mocks base method.
+func (m *MockService) ExportOPML(ctx context.Context, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportOPML", ctx, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportOPML indicates an expected call of ExportOPML.
+func (mr *MockServiceMockRecorder) ExportOPML(ctx, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportOPML", reflect.TypeOf((*MockService)(nil).ExportOPML), ctx, w)
+}
+
+// GetArticleRevisions mocks base method.
+func (m *MockService) GetArticleRevisions(ctx context.Context, id int) ([]models.ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArticleRevisions", ctx, id)
+	ret0, _ := ret[0].([]models.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArticleRevisions indicates an expected call of GetArticleRevisions.
+func (mr *MockServiceMockRecorder) GetArticleRevisions(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArticleRevisions", reflect.TypeOf((*MockService)(nil).GetArticleRevisions), ctx, id)
+}
+
 // GetArticles mocks base method.
-func (m *MockService) GetArticles(request models.GetArticlesRequest) (models.GetArticlesResponse, error) {
+func (m *MockService) GetArticles(ctx context.Context, request models.GetArticlesRequest) (models.GetArticlesResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetArticles", request)
+	ret := m.ctrl.Call(m, "GetArticles", ctx, request)
 	ret0, _ := ret[0].(models.GetArticlesResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetArticles indicates an expected call of GetArticles.
-func (mr *MockServiceMockRecorder) GetArticles(request interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) GetArticles(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArticles", reflect.TypeOf((*MockService)(nil).GetArticles), ctx, request)
+}
+
+// GetFeedStatus mocks base method.
+func (m *MockService) GetFeedStatus(ctx context.Context, id string) (models.FeedStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeedStatus", ctx, id)
+	ret0, _ := ret[0].(models.FeedStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeedStatus indicates an expected call of GetFeedStatus.
+func (mr *MockServiceMockRecorder) GetFeedStatus(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeedStatus", reflect.TypeOf((*MockService)(nil).GetFeedStatus), ctx, id)
+}
+
+// GetFeeds mocks base method.
+func (m *MockService) GetFeeds(ctx context.Context) ([]models.FeedStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeeds", ctx)
+	ret0, _ := ret[0].([]models.FeedStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeeds indicates an expected call of GetFeeds.
+func (mr *MockServiceMockRecorder) GetFeeds(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeeds", reflect.TypeOf((*MockService)(nil).GetFeeds), ctx)
+}
+
+// ImportOPML mocks base method.
+func (m *MockService) ImportOPML(ctx context.Context, r io.Reader) (models.FeedImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportOPML", ctx, r)
+	ret0, _ := ret[0].(models.FeedImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportOPML indicates an expected call of ImportOPML.
+func (mr *MockServiceMockRecorder) ImportOPML(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportOPML", reflect.TypeOf((*MockService)(nil).ImportOPML), ctx, r)
+}
+
+// Suggest mocks base method.
+func (m *MockService) Suggest(ctx context.Context, prefix string) ([]models.Suggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Suggest", ctx, prefix)
+	ret0, _ := ret[0].([]models.Suggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Suggest indicates an expected call of Suggest.
+func (mr *MockServiceMockRecorder) Suggest(ctx, prefix interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Suggest", reflect.TypeOf((*MockService)(nil).Suggest), ctx, prefix)
+}
+
+// UpdateFeed mocks base method.
+func (m *MockService) UpdateFeed(ctx context.Context, id string, update models.FeedUpdate) (models.Feed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFeed", ctx, id, update)
+	ret0, _ := ret[0].(models.Feed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateFeed indicates an expected call of UpdateFeed.
+func (mr *MockServiceMockRecorder) UpdateFeed(ctx, id, update interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArticles", reflect.TypeOf((*MockService)(nil).GetArticles), request)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFeed", reflect.TypeOf((*MockService)(nil).UpdateFeed), ctx, id, update)
 }
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/moynur/news-app/internal/models"
+)
+
+// suggestionsPerKind is how many categories, providers and titles are suggested at most
+const suggestionsPerKind = 5
+
+// maxPrefixLength is the longest prefix suggestions are looked up for, in characters
+const maxPrefixLength = 100
+
+var ErrInvalidPrefix = errors.New("prefix must be between 1 and 100 characters")
+
+// Suggest returns the categories, then the providers, then the titles with a word starting with the prefix, for
+// completing a search as it's typed
+func (s *service) Suggest(ctx context.Context, prefix string) ([]models.Suggestion, error) {
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" || utf8.RuneCountInString(prefix) > maxPrefixLength {
+		return nil, ErrInvalidPrefix
+	}
+	suggestions, err := s.store.Suggest(ctx, prefix, suggestionsPerKind)
+	if err != nil {
+		return nil, err
+	}
+	mapped := make([]models.Suggestion, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		mapped = append(mapped, models.Suggestion{
+			Text: suggestion.Text,
+			Kind: string(suggestion.Kind),
+		})
+	}
+	return mapped, nil
+}
@@ -0,0 +1,58 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moynur/news-app/internal/models"
+	"github.com/moynur/news-app/internal/service"
+	"github.com/moynur/news-app/internal/store"
+)
+
+func Test_service_Suggest(t *testing.T) {
+	t.Run("maps the suggestions", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := store.NewMockStorer(ctrl)
+		s := service.NewService(ms, cursorSecret)
+		ms.EXPECT().Suggest(gomock.Any(), "pol", 5).Return([]store.Suggestion{
+			{Text: "politics", Kind: store.SuggestCategory},
+			{Text: "Politico", Kind: store.SuggestProvider},
+			{Text: "Polls close", Kind: store.SuggestTitle},
+		}, nil)
+
+		got, err := s.Suggest(context.Background(), " pol ")
+		assert.NoError(t, err)
+		assert.Equal(t, []models.Suggestion{
+			{Text: "politics", Kind: models.SuggestionCategory},
+			{Text: "Politico", Kind: models.SuggestionProvider},
+			{Text: "Polls close", Kind: models.SuggestionTitle},
+		}, got)
+	})
+
+	t.Run("returns an empty list when nothing starts with the prefix", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := store.NewMockStorer(ctrl)
+		s := service.NewService(ms, cursorSecret)
+		ms.EXPECT().Suggest(gomock.Any(), "xyz", 5).Return(nil, nil)
+
+		got, err := s.Suggest(context.Background(), "xyz")
+		assert.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("rejects a blank or long prefix", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := store.NewMockStorer(ctrl)
+		s := service.NewService(ms, cursorSecret)
+		ms.EXPECT().Suggest(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		for _, prefix := range []string{"", "  ", strings.Repeat("é", 101)} {
+			_, err := s.Suggest(context.Background(), prefix)
+			assert.ErrorIs(t, err, service.ErrInvalidPrefix, prefix)
+		}
+	})
+}
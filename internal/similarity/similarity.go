@@ -0,0 +1,96 @@
+package similarity
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"strings"
+	"unicode"
+)
+
+// MaxDistance is how many bits two fingerprints can differ by and still be the same story
+const MaxDistance = 12
+
+// titleWeight makes the headline count for more than the summary as publishers summarise the same story differently
+const titleWeight = 2
+
+// stopWords carry no meaning about what a story is about
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "but": true, "by": true,
+	"for": true, "from": true, "has": true, "have": true, "he": true, "her": true, "his": true, "in": true, "is": true,
+	"it": true, "its": true, "of": true, "on": true, "or": true, "she": true, "that": true, "the": true, "their": true,
+	"they": true, "this": true, "to": true, "was": true, "were": true, "will": true, "with": true, "after": true,
+	"over": true, "says": true, "said": true, "new": true,
+}
+
+// Fingerprint is a SimHash of the words and pairs of words in the title and summary, articles about the same story
+// have fingerprints which only differ by a few bits
+func Fingerprint(title string, summary string) uint64 {
+	var weights [64]int
+	add := func(text string, weight int) {
+		words := tokens(text)
+		for i, word := range words {
+			addFeature(&weights, word, weight)
+			if i > 0 {
+				addFeature(&weights, words[i-1]+" "+word, weight)
+			}
+		}
+	}
+	add(title, titleWeight)
+	add(summary, 1)
+	var fingerprint uint64
+	for bit, weight := range weights {
+		if weight > 0 {
+			fingerprint |= 1 << uint(bit)
+		}
+	}
+	return fingerprint
+}
+
+// Distance is how many bits the fingerprints differ by
+func Distance(a uint64, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Similar reports whether the fingerprints are close enough to be the same story
+func Similar(a uint64, b uint64) bool {
+	return Distance(a, b) <= MaxDistance
+}
+
+func addFeature(weights *[64]int, feature string, weight int) {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(feature))
+	hash := h.Sum64()
+	for bit := range weights {
+		if hash&(1<<uint(bit)) != 0 {
+			weights[bit] += weight
+		} else {
+			weights[bit] -= weight
+		}
+	}
+}
+
+// tokens are the lower-cased words in the text without punctuation or stop words
+func tokens(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.' && r != '%'
+	})
+	words := make([]string, 0, len(fields))
+	for _, field := range fields {
+		field = strings.Trim(field, ".")
+		if field == "" || stopWords[field] {
+			continue
+		}
+		words = append(words, stem(field))
+	}
+	return words
+}
+
+// stem is deliberately crude, it only has to make raises, raised and raising the same word
+func stem(word string) string {
+	for _, suffix := range []string{"ing", "ed", "es", "s"} {
+		if len(word) > len(suffix)+3 && strings.HasSuffix(word, suffix) {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+	return word
+}
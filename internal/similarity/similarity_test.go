@@ -0,0 +1,45 @@
+package similarity_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moynur/news-app/internal/similarity"
+)
+
+type article struct {
+	title   string
+	summary string
+}
+
+func TestSimilar(t *testing.T) {
+	rates := article{"Bank of England raises interest rates to 5.25%", "The Bank of England has raised interest rates to 5.25%, the highest level in 15 years."}
+	election := article{"Prime minister announces general election for 4 July", "The prime minister has announced a general election will be held on 4 July."}
+	tests := []struct {
+		name string
+		a    article
+		b    article
+		want bool
+	}{
+		{name: "identical", a: rates, b: rates, want: true},
+		{name: "case and punctuation", a: rates, b: article{"BANK OF ENGLAND RAISES INTEREST RATES TO 5.25%!", "The Bank of England has raised interest rates to 5.25% - the highest level in 15 years"}, want: true},
+		{name: "same story from another publisher", a: rates, b: article{"Bank of England raises interest rates to 5.25% in 14th consecutive hike", "The Bank of England has raised interest rates to 5.25%, the highest in 15 years, as it tries to curb inflation."}, want: true},
+		{name: "reordered headline", a: election, b: article{"General election to be held on 4 July, prime minister announces", "Rishi Sunak has called a general election for 4 July."}, want: true},
+		{name: "different story", a: rates, b: article{"England beat Australia in the first Ashes test", "England won by two wickets at Edgbaston in a thrilling finish."}, want: false},
+		{name: "different story on the same day", a: article{"Storm Babet: Flood warnings issued across Scotland", "Severe flood warnings are in place across Scotland as Storm Babet arrives."}, b: article{"Train strikes: Rail workers walk out again", "Rail workers have walked out again in a long running dispute over pay."}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := similarity.Fingerprint(tt.a.title, tt.a.summary)
+			b := similarity.Fingerprint(tt.b.title, tt.b.summary)
+			assert.Equal(t, tt.want, similarity.Similar(a, b), "distance %d", similarity.Distance(a, b))
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	assert.Equal(t, 0, similarity.Distance(0xff, 0xff))
+	assert.Equal(t, 64, similarity.Distance(0, ^uint64(0)))
+	assert.Equal(t, 2, similarity.Distance(0b1010, 0b0110))
+}
@@ -0,0 +1,114 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/moynur/news-app/internal/similarity"
+)
+
+// clusterWindow is how far apart two articles can be published and still be about the same story
+const clusterWindow = 48 * time.Hour
+
+// fingerprintBands is how many parts a fingerprint is split into, only articles with a part of their fingerprint in
+// common are compared. Fingerprints which differ by fewer bits than there are bands always have one in common and most
+// which are similar enough to be the same story do too, each band is indexed along with published_at
+const (
+	fingerprintBands = 8
+	bandBits         = 64 / fingerprintBands
+)
+
+// fingerprintBand is the value of one part of the fingerprint
+func fingerprintBand(fingerprint int64, band int) int64 {
+	return int64(uint64(fingerprint) >> uint(band*bandBits) & (1<<bandBits - 1))
+}
+
+// sharesBand reports whether the fingerprints have a part in common so are worth comparing
+func sharesBand(a int64, b int64) bool {
+	for band := 0; band < fingerprintBands; band++ {
+		if fingerprintBand(a, band) == fingerprintBand(b, band) {
+			return true
+		}
+	}
+	return false
+}
+
+// bandConditions matches the articles published between from and to whose fingerprint has a part in common with the
+// fingerprint, the expressions are written the same as the indexes on them so they're used
+func bandConditions(fingerprint int64, from time.Time, to time.Time) (string, []interface{}) {
+	conditions := make([]string, 0, fingerprintBands)
+	args := make([]interface{}, 0, fingerprintBands*3)
+	for band := 0; band < fingerprintBands; band++ {
+		conditions = append(conditions, fmt.Sprintf("((fingerprint >> %d) & %d = ? AND published_at BETWEEN ? AND ?)", band*bandBits, 1<<bandBits-1))
+		args = append(args, fingerprintBand(fingerprint, band), from, to)
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
+
+// assignCluster puts the article in the cluster of the most similar article published around the same time, or in a
+// cluster of its own when there isn't one
+func assignCluster(tx *gorm.DB, article *NewsArticle) error {
+	var candidates []NewsArticle
+	bands, args := bandConditions(article.Fingerprint, article.PublishedAt.Add(-clusterWindow), article.PublishedAt.Add(clusterWindow))
+	resp := tx.Select("id", "cluster_id", "fingerprint").
+		Where("id <> ?", article.ID).
+		Where(bands, args...).
+		Order("id").Find(&candidates)
+	if resp.Error != nil {
+		return fmt.Errorf("unable to find similar articles, %w", resp.Error)
+	}
+	article.ClusterID = closestCluster(*article, candidates)
+	resp = tx.Model(&NewsArticle{}).Where("id = ?", article.ID).Update("cluster_id", article.ClusterID)
+	if resp.Error != nil {
+		return fmt.Errorf("unable to assign cluster, %w", resp.Error)
+	}
+	return nil
+}
+
+// closestCluster is the cluster of the most similar candidate, or the article's own ID when none are similar enough
+func closestCluster(article NewsArticle, candidates []NewsArticle) uint {
+	cluster := article.ID
+	closest := similarity.MaxDistance + 1
+	for _, candidate := range candidates {
+		distance := similarity.Distance(uint64(article.Fingerprint), uint64(candidate.Fingerprint))
+		if distance < closest && candidate.ClusterID != 0 {
+			closest = distance
+			cluster = candidate.ClusterID
+		}
+	}
+	return cluster
+}
+
+// countRelated fills in how many other articles there are in each article's cluster
+func (s *Store) countRelated(ctx context.Context, articles []NewsArticle) error {
+	if len(articles) == 0 {
+		return nil
+	}
+	clusters := make([]uint, 0, len(articles))
+	for _, article := range articles {
+		clusters = append(clusters, article.ClusterID)
+	}
+	var counts []struct {
+		ClusterID uint
+		Size      int
+	}
+	resp := s.db.WithContext(ctx).Model(&NewsArticle{}).Select("cluster_id, COUNT(*) AS size").
+		Where("cluster_id IN ?", clusters).Group("cluster_id").Scan(&counts)
+	if resp.Error != nil {
+		return fmt.Errorf("unable to count related articles, %w", resp.Error)
+	}
+	sizes := make(map[uint]int, len(counts))
+	for _, count := range counts {
+		sizes[count.ClusterID] = count.Size
+	}
+	for i := range articles {
+		if size := sizes[articles[i].ClusterID]; size > 1 {
+			articles[i].Related = size - 1
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package store
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ConditionKind is what a Condition tests
+type ConditionKind int
+
+const (
+	// ConditionAll and ConditionAny are true when all or any of the Conditions are, ConditionNot negates its only one
+	ConditionAll ConditionKind = iota
+	ConditionAny
+	ConditionNot
+	// ConditionText is a full text search for every word in Value, ConditionPhrase needs them next to each other
+	ConditionText
+	ConditionPhrase
+	// ConditionTitle, ConditionDescription and ConditionProvider contain Value, ignoring case
+	ConditionTitle
+	ConditionDescription
+	ConditionProvider
+	// ConditionCategory is an article in the category named Value
+	ConditionCategory
+	// ConditionPublishedAfter and ConditionPublishedBefore compare when the article was published with Time
+	ConditionPublishedAfter
+	ConditionPublishedBefore
+)
+
+// Condition is a boolean expression over articles, the service builds one from a search query
+type Condition struct {
+	Kind       ConditionKind
+	Conditions []Condition
+	Value      string
+	Time       time.Time
+}
+
+// condition is the SQL for the condition, every value is a parameter so nothing needs quoting. When fuzzy is set text
+// and phrases match words spelt similarly to theirs, in any order
+func (s *Store) condition(c Condition, fuzzy bool) clause.Expr {
+	switch c.Kind {
+	case ConditionAll, ConditionAny:
+		if len(c.Conditions) == 0 {
+			if c.Kind == ConditionAll {
+				return gorm.Expr("1 = 1")
+			}
+			return gorm.Expr("1 = 0")
+		}
+		placeholders := make([]string, len(c.Conditions))
+		vars := make([]interface{}, len(c.Conditions))
+		for i, child := range c.Conditions {
+			placeholders[i] = "?"
+			vars[i] = s.condition(child, fuzzy)
+		}
+		operator := " AND "
+		if c.Kind == ConditionAny {
+			operator = " OR "
+		}
+		return gorm.Expr("("+strings.Join(placeholders, operator)+")", vars...)
+	case ConditionNot:
+		return gorm.Expr("NOT ?", s.condition(Condition{Kind: ConditionAll, Conditions: c.Conditions}, fuzzy))
+	case ConditionText, ConditionPhrase:
+		words := searchWords(c.Value)
+		if len(words) == 0 {
+			return gorm.Expr("1 = 1")
+		}
+		if fuzzy {
+			return s.dialect.fuzzyMatch(words)
+		}
+		return s.dialect.match(words, c.Kind == ConditionPhrase)
+	case ConditionTitle:
+		return containsExpr("news_articles.title", c.Value)
+	case ConditionDescription:
+		return containsExpr("news_articles.description", c.Value)
+	case ConditionProvider:
+		return containsExpr("news_articles.provider", c.Value)
+	case ConditionCategory:
+		return gorm.Expr("news_articles.id IN (SELECT article_categories.article_id FROM article_categories "+
+			"JOIN categories ON categories.id = article_categories.category_id WHERE categories.name = ?)", NormaliseCategory(c.Value))
+	case ConditionPublishedAfter:
+		return gorm.Expr("news_articles.published_at > ?", c.Time.UTC())
+	case ConditionPublishedBefore:
+		return gorm.Expr("news_articles.published_at < ?", c.Time.UTC())
+	}
+	return gorm.Expr("1 = 0")
+}
+
+// containsExpr matches the column containing the value ignoring case, the value's LIKE wildcards are escaped
+func containsExpr(column string, value string) clause.Expr {
+	return gorm.Expr("lower("+column+") LIKE ? ESCAPE '\\'", "%"+escapeLike(strings.ToLower(value))+"%")
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+// rankWords are the words articles are ranked and highlighted by, the words of every text condition which isn't negated
+func rankWords(filters Filters) []string {
+	if filters.Condition == nil {
+		return nil
+	}
+	words := conditionWords(*filters.Condition)
+	seen := map[string]bool{}
+	unique := words[:0]
+	for _, word := range words {
+		if !seen[word] {
+			seen[word] = true
+			unique = append(unique, word)
+		}
+	}
+	return unique
+}
+
+// Ranked is whether the condition has words articles can be ranked by, one of only fields and dates doesn't
+func (c Condition) Ranked() bool {
+	return len(conditionWords(c)) > 0
+}
+
+func conditionWords(c Condition) []string {
+	switch c.Kind {
+	case ConditionAll, ConditionAny:
+		var words []string
+		for _, child := range c.Conditions {
+			words = append(words, conditionWords(child)...)
+		}
+		return words
+	case ConditionText, ConditionPhrase:
+		return searchWords(c.Value)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package store
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+
+	"github.com/jackc/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/moynur/news-app/internal/migrate"
+)
+
+// the words which matched a search are wrapped in these in headlines
+const (
+	highlightStart = "<mark>"
+	highlightStop  = "</mark>"
+)
+
+// dialect is what's different about each database Store can use
+type dialect struct {
+	// migrations is the embedded directory of the database's migrations, lock stops them being applied twice at once
+	migrations string
+	lock       migrate.Locker
+	// inserted is returned for each article the upsert saves, it's false when the article was already stored
+	inserted string
+	// duplicateKey is whether the error is from a unique constraint
+	duplicateKey func(err error) bool
+	// match is true for articles with every word in their title, description or content, next to each other when
+	// they're a phrase
+	match func(words []string, phrase bool) clause.Expr
+	// rank is how well an article matches any of the words, higher is better. Matches in the title count for more than
+	// ones in the description, which count for more than ones in the content
+	rank func(words []string) clause.Expr
+	// headline is the article's description, or its title when it doesn't have one, with any of the words highlighted
+	headline func(words []string) clause.Expr
+	// fuzzyMatch is true for articles with a word spelt similarly to each of the words in their title or description,
+	// fuzzyRank is how similar they are, higher is better
+	fuzzyMatch func(words []string) clause.Expr
+	fuzzyRank  func(words []string) clause.Expr
+}
+
+var postgresDialect = dialect{
+	migrations: "migrations/postgres",
+	lock:       migrate.PostgresLock,
+	// xmax is only zero for a row which has just been inserted
+	inserted: "(xmax = 0) AS inserted",
+	duplicateKey: func(err error) bool {
+		var pgErr *pgconn.PgError
+		return errors.As(err, &pgErr) && pgErr.Code == ErrDuplicateKey
+	},
+	// the search column is a weighted tsvector of the title, description and content
+	match: func(words []string, phrase bool) clause.Expr {
+		if phrase {
+			return gorm.Expr("news_articles.search @@ phraseto_tsquery('english', ?)", strings.Join(words, " "))
+		}
+		return gorm.Expr("news_articles.search @@ plainto_tsquery('english', ?)", strings.Join(words, " "))
+	},
+	// words are only letters and numbers so they can be put in a tsquery as they are
+	rank: func(words []string) clause.Expr {
+		return gorm.Expr("ts_rank(news_articles.search, to_tsquery('english', ?))", strings.Join(words, " | "))
+	},
+	headline: func(words []string) clause.Expr {
+		return gorm.Expr("ts_headline('english', coalesce(nullif(news_articles.description, ''), news_articles.title), "+
+			"to_tsquery('english', ?), ?)", strings.Join(words, " | "),
+			"StartSel="+highlightStart+", StopSel="+highlightStop+", MinWords=15, MaxWords=35")
+	},
+	// <% is true when the word is similar enough to any part of the text, it's the same text idx_news_articles_fuzzy
+	// is on so the index can be used
+	fuzzyMatch: func(words []string) clause.Expr {
+		matches := make([]string, len(words))
+		vars := make([]interface{}, len(words))
+		for i, word := range words {
+			matches[i] = "? <% " + postgresFuzzyText
+			vars[i] = word
+		}
+		return gorm.Expr("("+strings.Join(matches, " AND ")+")", vars...)
+	},
+	fuzzyRank: func(words []string) clause.Expr {
+		return gorm.Expr("word_similarity(?, "+postgresFuzzyText+")", strings.Join(words, " "))
+	},
+}
+
+const postgresFuzzyText = "lower(news_articles.title || ' ' || coalesce(news_articles.description, ''))"
+
+// searchWords splits a search into lower case words, anything other than a letter or a number separates them
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// similarityThreshold is the share of a word's trigrams another word has to have to be spelt similarly, it's the same
+// as pg_trgm's word_similarity_threshold
+const similarityThreshold = 0.6
+
+// trigrams are the distinct sequences of three letters in the word, padded the same way as pg_trgm with two spaces at
+// the start and one at the end so the first letters count for more
+func trigrams(word string) []string {
+	runes := []rune("  " + word + " ")
+	var found []string
+	seen := map[string]bool{}
+	for i := 0; i+3 <= len(runes); i++ {
+		trigram := string(runes[i : i+3])
+		if !seen[trigram] {
+			seen[trigram] = true
+			found = append(found, trigram)
+		}
+	}
+	return found
+}
+
+// wordSimilarity is the share of the word's trigrams the other word has
+func wordSimilarity(word string, other string) float64 {
+	wordTrigrams := trigrams(word)
+	otherTrigrams := map[string]bool{}
+	for _, trigram := range trigrams(other) {
+		otherTrigrams[trigram] = true
+	}
+	var shared int
+	for _, trigram := range wordTrigrams {
+		if otherTrigrams[trigram] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(wordTrigrams))
+}
@@ -0,0 +1,180 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Feed is a source of articles the feeder polls, feeds are identified by their name
+type Feed struct {
+	Name                   string `gorm:"primaryKey"`
+	URL                    string
+	RefreshIntervalSeconds int
+	DefaultCategory        string
+	FallbackImage          string
+	Enabled                bool
+	CreatedAt              time.Time
+	UpdatedAt              time.Time
+}
+
+// FeedState is what the feeder remembers about a feed between polls so it only downloads it when it has changed
+type FeedState struct {
+	Name         string `gorm:"primaryKey"`
+	ETag         string `gorm:"column:etag"`
+	LastModified string
+	// TTLMinutes, SkipHours and SkipDays are the hints from the last copy of the feed, kept so a 304 still honours them
+	TTLMinutes    int
+	SkipHours     string
+	SkipDays      string
+	LastFetchedAt *time.Time
+	NextFetchAt   *time.Time
+	// ConsecutiveFailures resets on every successful fetch, once it gets too high the feed is Paused
+	ConsecutiveFailures int
+	LastSuccessAt       *time.Time
+	LastError           string
+	Paused              bool
+	// the outcome of the last time the feed was fetched
+	LastStatus            int
+	LastItemsSeen         int
+	LastItemsInserted     int
+	LastItemsUpdated      int
+	LastDuplicatesSkipped int
+}
+
+func (s *Store) GetFeeds(ctx context.Context) ([]Feed, error) {
+	var feeds []Feed
+	resp := s.db.WithContext(ctx).Order("name asc").Find(&feeds)
+	if resp.Error != nil {
+		return nil, fmt.Errorf("failed to get feeds %w", resp.Error)
+	}
+	return feeds, nil
+}
+
+func (s *Store) GetFeed(ctx context.Context, name string) (Feed, error) {
+	var feed Feed
+	resp := s.db.WithContext(ctx).Where("name = ?", name).First(&feed)
+	if resp.Error != nil {
+		if errors.Is(resp.Error, gorm.ErrRecordNotFound) {
+			return Feed{}, ErrNotFound
+		}
+		return Feed{}, fmt.Errorf("failed to get feed %w", resp.Error)
+	}
+	return feed, nil
+}
+
+func (s *Store) CreateFeed(ctx context.Context, feed Feed) error {
+	resp := s.db.WithContext(ctx).Create(&feed)
+	if resp.Error != nil {
+		if s.dialect.duplicateKey(resp.Error) {
+			return ErrAlreadyExists
+		}
+		return fmt.Errorf("unable to create feed, %w", resp.Error)
+	}
+	return nil
+}
+
+// CreateFeedsIfNotExist is used to seed the feeds, any feed which already exists is left as it is
+func (s *Store) CreateFeedsIfNotExist(ctx context.Context, feeds []Feed) error {
+	if len(feeds) == 0 {
+		return nil
+	}
+	resp := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&feeds)
+	if resp.Error != nil {
+		return fmt.Errorf("unable to create feeds, %w", resp.Error)
+	}
+	return nil
+}
+
+func (s *Store) UpdateFeed(ctx context.Context, feed Feed) error {
+	// a map is used so zero values like disabling the feed are still written
+	resp := s.db.WithContext(ctx).Model(&Feed{}).Where("name = ?", feed.Name).Updates(map[string]interface{}{
+		"url":                      feed.URL,
+		"refresh_interval_seconds": feed.RefreshIntervalSeconds,
+		"default_category":         feed.DefaultCategory,
+		"fallback_image":           feed.FallbackImage,
+		"enabled":                  feed.Enabled,
+	})
+	if resp.Error != nil {
+		return fmt.Errorf("unable to update feed, %w", resp.Error)
+	}
+	if resp.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeleteFeed removes the feed and everything the feeder knew about it, its articles are kept
+func (s *Store) DeleteFeed(ctx context.Context, name string) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		resp := tx.Where("name = ?", name).Delete(&Feed{})
+		if resp.Error != nil {
+			return fmt.Errorf("unable to delete feed, %w", resp.Error)
+		}
+		if resp.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		resp = tx.Where("name = ?", name).Delete(&FeedState{})
+		if resp.Error != nil {
+			return fmt.Errorf("unable to delete feed state, %w", resp.Error)
+		}
+		return nil
+	})
+}
+
+// GetFeedState returns what is known about the feed, a feed which has never been fetched has an empty state
+func (s *Store) GetFeedState(ctx context.Context, name string) (FeedState, error) {
+	state := FeedState{Name: name}
+	resp := s.db.WithContext(ctx).Where("name = ?", name).Limit(1).Find(&state)
+	if resp.Error != nil {
+		return FeedState{}, fmt.Errorf("failed to get feed state %w", resp.Error)
+	}
+	return state, nil
+}
+
+func (s *Store) GetFeedStates(ctx context.Context) ([]FeedState, error) {
+	var states []FeedState
+	resp := s.db.WithContext(ctx).Order("name asc").Find(&states)
+	if resp.Error != nil {
+		return nil, fmt.Errorf("failed to get feed states %w", resp.Error)
+	}
+	return states, nil
+}
+
+// SaveFeedState records what is known about the feed. The feed's row is locked while its state is saved so a feed
+// deleted while it was being fetched isn't given its state back, ErrNotFound is returned instead
+func (s *Store) SaveFeedState(ctx context.Context, state FeedState) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var feed Feed
+		resp := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("name = ?", state.Name).Limit(1).Find(&feed)
+		if resp.Error != nil {
+			return fmt.Errorf("unable to get feed, %w", resp.Error)
+		}
+		if resp.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		resp = tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&state)
+		if resp.Error != nil {
+			return fmt.Errorf("unable to save feed state, %w", resp.Error)
+		}
+		return nil
+	})
+}
+
+// ResumeFeed clears a pause caused by the feed failing too many times. A paused feed is still checked every refresh
+// interval, so it is fetched again at the next one rather than straight away
+func (s *Store) ResumeFeed(ctx context.Context, name string) error {
+	resp := s.db.WithContext(ctx).Model(&FeedState{}).Where("name = ?", name).Updates(map[string]interface{}{
+		"paused":               false,
+		"consecutive_failures": 0,
+		"next_fetch_at":        nil,
+	})
+	if resp.Error != nil {
+		return fmt.Errorf("unable to resume feed, %w", resp.Error)
+	}
+	return nil
+}
@@ -0,0 +1,761 @@
+package store
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps everything in memory so the service can be run without a database, nothing survives a restart.
+// It behaves the same as Store, which the tests in storetest check
+type MemoryStore struct {
+	mu sync.RWMutex
+	// articles, revisions and categories are in the order they were created, their ID is their index plus one
+	articles   []NewsArticle
+	revisions  []ArticleRevision
+	categories []Category
+	feeds      map[string]Feed
+	states     map[string]FeedState
+}
+
+var _ Storer = (*MemoryStore)(nil)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		feeds:  map[string]Feed{},
+		states: map[string]FeedState{},
+	}
+}
+
+// UpsertArticles saves a batch of articles the same way Store does
+func (m *MemoryStore) UpsertArticles(ctx context.Context, articles []NewsArticle) (UpsertResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result UpsertResult
+	articles = uniqueArticles(articles, &result)
+	// every article is matched before any are saved so articles in the same batch never match each other
+	existing := make([]int, len(articles))
+	for i, request := range articles {
+		existing[i] = m.find(request)
+	}
+	now := time.Now()
+	var created []NewsArticle
+	for i, request := range articles {
+		if existing[i] < 0 {
+			created = append(created, request)
+			continue
+		}
+		result.add(m.update(existing[i], request, now))
+	}
+	for _, request := range created {
+		m.insert(request, now)
+		result.add(ArticleCreated)
+	}
+	return result, nil
+}
+
+// find is the index of the article stored under one of the request's link keys, or -1 when there isn't one
+func (m *MemoryStore) find(request NewsArticle) int {
+	for _, key := range linkKeys(request) {
+		for i, article := range m.articles {
+			if article.LinkKey == key {
+				return i
+			}
+		}
+		for i, article := range m.articles {
+			for _, source := range article.Sources {
+				if source.LinkKey == key {
+					return i
+				}
+			}
+		}
+	}
+	return -1
+}
+
+func (m *MemoryStore) insert(request NewsArticle, now time.Time) {
+	article := copyArticle(request)
+	article.ID = uint(len(m.articles) + 1)
+	if article.Thumbnail == "" {
+		article.Thumbnail = m.feeds[article.Source].FallbackImage
+	}
+	if article.CreatedAt.IsZero() {
+		article.CreatedAt = now
+	}
+	article.Inserted = false
+	article.Related = 0
+	article.Sources = nil
+	addMemorySources(&article, request.Sources, now)
+	article.Categories = nil
+	for _, category := range request.Categories {
+		m.linkCategory(&article, category.Name)
+	}
+	article.ClusterID = m.cluster(article)
+	m.articles = append(m.articles, article)
+}
+
+// cluster finds the article's cluster from the articles published around the same time with a part of their
+// fingerprint in common, the same as the database does
+func (m *MemoryStore) cluster(article NewsArticle) uint {
+	var candidates []NewsArticle
+	for _, other := range m.articles {
+		if other.ID != article.ID && sharesBand(article.Fingerprint, other.Fingerprint) &&
+			!other.PublishedAt.Before(article.PublishedAt.Add(-clusterWindow)) && !other.PublishedAt.After(article.PublishedAt.Add(clusterWindow)) {
+			candidates = append(candidates, other)
+		}
+	}
+	return closestCluster(article, candidates)
+}
+
+func (m *MemoryStore) update(index int, request NewsArticle, now time.Time) ArticleChange {
+	existing := &m.articles[index]
+	addMemorySources(existing, request.Sources, now)
+	if !changed(*existing, request) {
+		return ArticleUnchanged
+	}
+	m.revisions = append(m.revisions, ArticleRevision{
+		ID:          uint(len(m.revisions) + 1),
+		ArticleID:   existing.ID,
+		Title:       existing.Title,
+		Description: existing.Description,
+		Thumbnail:   existing.Thumbnail,
+		CreatedAt:   now,
+	})
+	existing.Title = request.Title
+	existing.Description = request.Description
+	existing.Content = request.Content
+	if request.Thumbnail != "" {
+		existing.Thumbnail = request.Thumbnail
+	}
+	existing.SourceUpdatedAt = copyTime(request.SourceUpdatedAt)
+	if existing.Fingerprint != request.Fingerprint {
+		existing.Fingerprint = request.Fingerprint
+		existing.ClusterID = m.cluster(*existing)
+	}
+	return ArticleUpdated
+}
+
+// addMemorySources records the feeds the article has been seen in, a feed which has been recorded before is left as
+// it is
+func addMemorySources(article *NewsArticle, sources []ArticleSource, now time.Time) {
+	for _, source := range sources {
+		seen := false
+		for _, existing := range article.Sources {
+			seen = seen || existing.Source == source.Source
+		}
+		if seen {
+			continue
+		}
+		source.ArticleID = article.ID
+		if source.CreatedAt.IsZero() {
+			source.CreatedAt = now
+		}
+		article.Sources = append(article.Sources, source)
+	}
+}
+
+// linkCategory creates the category if it hasn't been seen before and links it to the article
+func (m *MemoryStore) linkCategory(article *NewsArticle, name string) {
+	name = NormaliseCategory(name)
+	if name == "" {
+		return
+	}
+	var category *Category
+	for i := range m.categories {
+		if m.categories[i].Name == name {
+			category = &m.categories[i]
+		}
+	}
+	if category == nil {
+		m.categories = append(m.categories, Category{ID: uint(len(m.categories) + 1), Name: name})
+		category = &m.categories[len(m.categories)-1]
+	}
+	for _, linked := range article.Categories {
+		if linked.ID == category.ID {
+			return
+		}
+	}
+	article.Categories = append(article.Categories, *category)
+}
+
+func (m *MemoryStore) GetArticleRevisions(ctx context.Context, articleID int) ([]ArticleRevision, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if articleID <= 0 || articleID > len(m.articles) {
+		return nil, ErrNotFound
+	}
+	revisions := []ArticleRevision{}
+	for i := len(m.revisions) - 1; i >= 0; i-- {
+		if m.revisions[i].ArticleID == uint(articleID) {
+			revisions = append(revisions, m.revisions[i])
+		}
+	}
+	return revisions, nil
+}
+
+// GetRecordsAfterID returns the matching articles after the one with the ID provided, ordered the same way as Store
+func (m *MemoryStore) GetRecordsAfterID(ctx context.Context, ID int, numberOfRecords int, filters Filters) ([]NewsArticle, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var matching []NewsArticle
+	for _, article := range m.articles {
+		if matchesFilters(article, filters) {
+			matching = append(matching, article)
+		}
+	}
+	if filters.CollapseClusters {
+		// an article is only returned when it's the first in its cluster to match the filters
+		first := map[uint]bool{}
+		var collapsed []NewsArticle
+		for _, article := range matching {
+			if article.ClusterID != 0 && first[article.ClusterID] {
+				continue
+			}
+			first[article.ClusterID] = true
+			collapsed = append(collapsed, article)
+		}
+		matching = collapsed
+	}
+
+	words := rankWords(filters)
+	var before func(a NewsArticle, b NewsArticle) bool
+	switch {
+	case filters.Sort == SortPublished:
+		before = publishedBefore
+	case filters.Sort == SortRelevance && len(words) > 0:
+		before = func(a NewsArticle, b NewsArticle) bool { return rankedBefore(a, b, words, filters.Fuzzy) }
+	default:
+		before = func(a NewsArticle, b NewsArticle) bool { return a.ID < b.ID }
+	}
+	if filters.Descending && !(filters.Sort == SortRelevance && len(words) > 0) {
+		ascending := before
+		before = func(a NewsArticle, b NewsArticle) bool { return ascending(b, a) }
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		return before(matching[i], matching[j])
+	})
+	after := func(NewsArticle) bool { return true }
+	if ID > 0 {
+		if ID > len(m.articles) {
+			return []NewsArticle{}, nil
+		}
+		cursor := m.articles[ID-1]
+		after = func(article NewsArticle) bool { return before(cursor, article) }
+		if filters.Before {
+			after = func(article NewsArticle) bool { return before(article, cursor) }
+		}
+	}
+	// records before the cursor are read backwards from it so the closest ones are found, then put back in order
+	if filters.Before {
+		reverseArticles(matching)
+	}
+
+	records := []NewsArticle{}
+	for _, article := range matching {
+		if numberOfRecords > 0 && len(records) == numberOfRecords {
+			break
+		}
+		if !after(article) {
+			continue
+		}
+		record := copyArticle(article)
+		if len(words) > 0 && !filters.Fuzzy {
+			record.Headline = headline(article, words)
+		}
+		if filters.CollapseClusters {
+			for _, other := range m.articles {
+				if other.ID != article.ID && other.ClusterID == article.ClusterID {
+					record.Related++
+				}
+			}
+		}
+		records = append(records, record)
+	}
+	if filters.Before {
+		reverseArticles(records)
+	}
+	return records, nil
+}
+
+// Suggest returns the same suggestions as Store
+func (m *MemoryStore) Suggest(ctx context.Context, prefix string, limit int) ([]Suggestion, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	var categories, providers []string
+	for _, category := range m.categories {
+		if startsWord(category.Name, prefix) {
+			categories = append(categories, category.Name)
+		}
+	}
+	published := map[string]NewsArticle{}
+	for _, article := range m.articles {
+		if startsWord(strings.ToLower(article.Provider), prefix) && !containsString(providers, article.Provider) {
+			providers = append(providers, article.Provider)
+		}
+		if startsWord(strings.ToLower(article.Title), prefix) {
+			if latest, ok := published[article.Title]; !ok || article.PublishedAt.After(latest.PublishedAt) {
+				published[article.Title] = article
+			}
+		}
+	}
+	var titles []string
+	for title := range published {
+		titles = append(titles, title)
+	}
+	sort.Strings(categories)
+	sort.Strings(providers)
+	sort.Slice(titles, func(i, j int) bool {
+		a, b := published[titles[i]].PublishedAt, published[titles[j]].PublishedAt
+		if !a.Equal(b) {
+			return a.After(b)
+		}
+		return titles[i] < titles[j]
+	})
+	var suggestions []Suggestion
+	for _, found := range []struct {
+		kind  SuggestionKind
+		texts []string
+	}{{SuggestCategory, categories}, {SuggestProvider, providers}, {SuggestTitle, titles}} {
+		for i, text := range found.texts {
+			if i == limit {
+				break
+			}
+			suggestions = append(suggestions, Suggestion{Text: text, Kind: found.kind})
+		}
+	}
+	return suggestions, nil
+}
+
+// startsWord is whether the text starts with the prefix or has a space followed by it
+func startsWord(text string, prefix string) bool {
+	return strings.HasPrefix(text, prefix) || strings.Contains(text, " "+prefix)
+}
+
+// publishedBefore orders articles by when they were published and then by ID
+func publishedBefore(a NewsArticle, b NewsArticle) bool {
+	if !a.PublishedAt.Equal(b.PublishedAt) {
+		return a.PublishedAt.Before(b.PublishedAt)
+	}
+	return a.ID < b.ID
+}
+
+func matchesFilters(article NewsArticle, filters Filters) bool {
+	if filters.Title != "" && !like(article.Title, filters.Title) {
+		return false
+	}
+	if filters.Description != "" && !like(article.Description, filters.Description) {
+		return false
+	}
+	if filters.Link != "" && !like(article.Link, filters.Link) {
+		return false
+	}
+	if len(filters.Categories) > 0 {
+		matched := map[string]bool{}
+		for _, category := range article.Categories {
+			for _, name := range filters.Categories {
+				if category.Name == name {
+					matched[name] = true
+				}
+			}
+		}
+		if len(matched) == 0 || (filters.MatchAllCategories && len(matched) != len(filters.Categories)) {
+			return false
+		}
+	}
+	if filters.Provider != "" && article.Provider != filters.Provider {
+		return false
+	}
+	if filters.CreatedAfter != nil && !article.CreatedAt.After(*filters.CreatedAfter) {
+		return false
+	}
+	if filters.CreatedBefore != nil && !article.CreatedAt.Before(*filters.CreatedBefore) {
+		return false
+	}
+	if filters.PublishedAfter != nil && !article.PublishedAt.After(*filters.PublishedAfter) {
+		return false
+	}
+	if filters.PublishedBefore != nil && !article.PublishedAt.Before(*filters.PublishedBefore) {
+		return false
+	}
+	if filters.Condition != nil && !matchesCondition(article, *filters.Condition, filters.Fuzzy) {
+		return false
+	}
+	return true
+}
+
+func matchesCondition(article NewsArticle, c Condition, fuzzy bool) bool {
+	switch c.Kind {
+	case ConditionAll:
+		for _, child := range c.Conditions {
+			if !matchesCondition(article, child, fuzzy) {
+				return false
+			}
+		}
+		return true
+	case ConditionAny:
+		for _, child := range c.Conditions {
+			if matchesCondition(article, child, fuzzy) {
+				return true
+			}
+		}
+		return false
+	case ConditionNot:
+		return !matchesCondition(article, Condition{Kind: ConditionAll, Conditions: c.Conditions}, fuzzy)
+	case ConditionText, ConditionPhrase:
+		return containsText(article, searchWords(c.Value), c.Kind == ConditionPhrase, fuzzy)
+	case ConditionTitle:
+		return strings.Contains(strings.ToLower(article.Title), strings.ToLower(c.Value))
+	case ConditionDescription:
+		return strings.Contains(strings.ToLower(article.Description), strings.ToLower(c.Value))
+	case ConditionProvider:
+		return strings.Contains(strings.ToLower(article.Provider), strings.ToLower(c.Value))
+	case ConditionCategory:
+		for _, category := range article.Categories {
+			if category.Name == NormaliseCategory(c.Value) {
+				return true
+			}
+		}
+		return false
+	case ConditionPublishedAfter:
+		return article.PublishedAt.After(c.Time)
+	case ConditionPublishedBefore:
+		return article.PublishedAt.Before(c.Time)
+	}
+	return false
+}
+
+// containsText is whether the article has the words, next to each other when they're a phrase, or words spelt like
+// them in its title or description when the search is fuzzy
+func containsText(article NewsArticle, words []string, phrase bool, fuzzy bool) bool {
+	switch {
+	case fuzzy:
+		return containsSimilarWords(article, words)
+	case phrase:
+		return containsPhrase(article, words)
+	}
+	return containsWords(article, words)
+}
+
+// containsPhrase is whether the words are next to each other in the article's title, description or content
+func containsPhrase(article NewsArticle, phrase []string) bool {
+	if len(phrase) == 0 {
+		return true
+	}
+	for _, text := range []string{article.Title, article.Description, article.Content} {
+		words := searchWords(text)
+		for start := 0; start+len(phrase) <= len(words); start++ {
+			if equalStrings(words[start:start+len(phrase)], phrase) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func equalStrings(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// rankedBefore orders the articles which match the words best first and then by ID
+func rankedBefore(a NewsArticle, b NewsArticle, words []string, fuzzy bool) bool {
+	rank := rank
+	if fuzzy {
+		rank = fuzzyRank
+	}
+	rankA, rankB := rank(a, words), rank(b, words)
+	if rankA != rankB {
+		return rankA > rankB
+	}
+	return a.ID < b.ID
+}
+
+// rank counts how many times the words appear, weighting the title over the description over the content the same
+// way as Postgres' ts_rank
+func rank(article NewsArticle, words []string) float64 {
+	var total float64
+	for _, field := range []struct {
+		text   string
+		weight float64
+	}{{article.Title, 1}, {article.Description, 0.4}, {article.Content, 0.2}} {
+		for _, word := range searchWords(field.text) {
+			if containsString(words, word) {
+				total += field.weight
+			}
+		}
+	}
+	return total
+}
+
+// fuzzyRank adds up how similar the word spelt most like each of the words is
+func fuzzyRank(article NewsArticle, words []string) float64 {
+	var total float64
+	for _, word := range words {
+		var best float64
+		for _, other := range searchWords(article.Title + " " + article.Description) {
+			if similar := wordSimilarity(word, other); similar > best {
+				best = similar
+			}
+		}
+		total += best
+	}
+	return total
+}
+
+// headline highlights the words in the description, or the title when there isn't a description
+func headline(article NewsArticle, words []string) string {
+	text := article.Description
+	if text == "" {
+		text = article.Title
+	}
+	return wordPattern.ReplaceAllStringFunc(text, func(word string) string {
+		if !containsString(words, strings.ToLower(word)) {
+			return word
+		}
+		return highlightStart + word + highlightStop
+	})
+}
+
+// wordPattern matches the same words as searchWords
+var wordPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// containsWords is whether every word is in the article's title, description or content. Unlike the databases the
+// words have to match exactly, "storms" won't find "storm"
+func containsWords(article NewsArticle, words []string) bool {
+	found := map[string]bool{}
+	for _, word := range searchWords(article.Title + " " + article.Description + " " + article.Content) {
+		found[word] = true
+	}
+	for _, word := range words {
+		if !found[word] {
+			return false
+		}
+	}
+	return true
+}
+
+// containsSimilarWords is whether there's a word spelt similarly to each of the words in the article's title or
+// description
+func containsSimilarWords(article NewsArticle, words []string) bool {
+	found := searchWords(article.Title + " " + article.Description)
+	for _, word := range words {
+		similar := false
+		for _, other := range found {
+			if wordSimilarity(word, other) >= similarityThreshold {
+				similar = true
+				break
+			}
+		}
+		if !similar {
+			return false
+		}
+	}
+	return true
+}
+
+// like matches the value the same way as SQL's LIKE, % is any number of characters, _ is any one character and a
+// backslash escapes either of them
+func like(value string, pattern string) bool {
+	var expression strings.Builder
+	expression.WriteString(`(?s)^`)
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			expression.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			expression.WriteString(`.*`)
+		case r == '_':
+			expression.WriteString(`.`)
+		default:
+			expression.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expression.WriteString(`$`)
+	return regexp.MustCompile(expression.String()).MatchString(value)
+}
+
+func (m *MemoryStore) GetFeeds(ctx context.Context) ([]Feed, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	feeds := make([]Feed, 0, len(m.feeds))
+	for _, feed := range m.feeds {
+		feeds = append(feeds, feed)
+	}
+	sort.Slice(feeds, func(i, j int) bool {
+		return feeds[i].Name < feeds[j].Name
+	})
+	return feeds, nil
+}
+
+func (m *MemoryStore) GetFeed(ctx context.Context, name string) (Feed, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	feed, ok := m.feeds[name]
+	if !ok {
+		return Feed{}, ErrNotFound
+	}
+	return feed, nil
+}
+
+func (m *MemoryStore) CreateFeed(ctx context.Context, feed Feed) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.feeds[feed.Name]; ok {
+		return ErrAlreadyExists
+	}
+	m.createFeed(feed)
+	return nil
+}
+
+// CreateFeedsIfNotExist is used to seed the feeds, any feed which already exists is left as it is
+func (m *MemoryStore) CreateFeedsIfNotExist(ctx context.Context, feeds []Feed) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, feed := range feeds {
+		if _, ok := m.feeds[feed.Name]; !ok {
+			m.createFeed(feed)
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) createFeed(feed Feed) {
+	now := time.Now()
+	if feed.CreatedAt.IsZero() {
+		feed.CreatedAt = now
+	}
+	if feed.UpdatedAt.IsZero() {
+		feed.UpdatedAt = now
+	}
+	m.feeds[feed.Name] = feed
+}
+
+func (m *MemoryStore) UpdateFeed(ctx context.Context, feed Feed) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existing, ok := m.feeds[feed.Name]
+	if !ok {
+		return ErrNotFound
+	}
+	existing.URL = feed.URL
+	existing.RefreshIntervalSeconds = feed.RefreshIntervalSeconds
+	existing.DefaultCategory = feed.DefaultCategory
+	existing.FallbackImage = feed.FallbackImage
+	existing.Enabled = feed.Enabled
+	existing.UpdatedAt = time.Now()
+	m.feeds[feed.Name] = existing
+	return nil
+}
+
+// DeleteFeed removes the feed and everything the feeder knew about it, its articles are kept
+func (m *MemoryStore) DeleteFeed(ctx context.Context, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.feeds[name]; !ok {
+		return ErrNotFound
+	}
+	delete(m.feeds, name)
+	delete(m.states, name)
+	return nil
+}
+
+// GetFeedState returns what is known about the feed, a feed which has never been fetched has an empty state
+func (m *MemoryStore) GetFeedState(ctx context.Context, name string) (FeedState, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	state, ok := m.states[name]
+	if !ok {
+		return FeedState{Name: name}, nil
+	}
+	return copyFeedState(state), nil
+}
+
+func (m *MemoryStore) GetFeedStates(ctx context.Context) ([]FeedState, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	states := make([]FeedState, 0, len(m.states))
+	for _, state := range m.states {
+		states = append(states, copyFeedState(state))
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Name < states[j].Name
+	})
+	return states, nil
+}
+
+// SaveFeedState records what is known about the feed, ErrNotFound is returned for a feed deleted while it was being
+// fetched so it isn't given its state back
+func (m *MemoryStore) SaveFeedState(ctx context.Context, state FeedState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.feeds[state.Name]; !ok {
+		return ErrNotFound
+	}
+	m.states[state.Name] = copyFeedState(state)
+	return nil
+}
+
+// ResumeFeed clears a pause caused by the feed failing too many times. A paused feed is still checked every refresh
+// interval, so it is fetched again at the next one rather than straight away
+func (m *MemoryStore) ResumeFeed(ctx context.Context, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state, ok := m.states[name]
+	if !ok {
+		return nil
+	}
+	state.Paused = false
+	state.ConsecutiveFailures = 0
+	state.NextFetchAt = nil
+	m.states[name] = state
+	return nil
+}
+
+// copyArticle copies everything the article refers to so callers can't change what's stored
+func copyArticle(article NewsArticle) NewsArticle {
+	article.Authors = append(StringList(nil), article.Authors...)
+	article.Enclosures = append(Enclosures(nil), article.Enclosures...)
+	article.Categories = append([]Category(nil), article.Categories...)
+	article.Sources = append([]ArticleSource(nil), article.Sources...)
+	article.SourceUpdatedAt = copyTime(article.SourceUpdatedAt)
+	return article
+}
+
+func copyFeedState(state FeedState) FeedState {
+	state.LastFetchedAt = copyTime(state.LastFetchedAt)
+	state.NextFetchAt = copyTime(state.NextFetchAt)
+	state.LastSuccessAt = copyTime(state.LastSuccessAt)
+	return state
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	copied := *t
+	return &copied
+}
@@ -0,0 +1,14 @@
+package store_test
+
+import (
+	"testing"
+
+	"github.com/moynur/news-app/internal/store"
+	"github.com/moynur/news-app/internal/store/storetest"
+)
+
+func TestMemoryStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Storer {
+		return store.NewMemoryStore()
+	})
+}
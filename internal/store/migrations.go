@@ -0,0 +1,24 @@
+package store
+
+import (
+	"embed"
+	"fmt"
+
+	"github.com/moynur/news-app/internal/migrate"
+)
+
+//go:embed migrations/postgres/*.sql migrations/sqlite/*.sql
+var migrationFiles embed.FS
+
+// Migrator applies the schema migrations embedded in the binary for the store's database
+func (s *Store) Migrator() (*migrate.Migrator, error) {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get database connection, %w", err)
+	}
+	migrations, err := migrate.Load(migrationFiles, s.dialect.migrations)
+	if err != nil {
+		return nil, err
+	}
+	return migrate.New(sqlDB, migrations, s.dialect.lock), nil
+}
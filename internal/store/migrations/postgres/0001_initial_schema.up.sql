@@ -7,4 +7,4 @@ CREATE TABLE IF NOT EXISTS news_articles
     thumbnail TEXT NOT NULL,
     category TEXT,
     created_at timestamp DEFAULT current_timestamp
-)
\ No newline at end of file
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_news_articles_provider;
+ALTER TABLE news_articles DROP COLUMN IF EXISTS provider;
+ALTER TABLE news_articles DROP COLUMN IF EXISTS source;
@@ -0,0 +1,4 @@
+ALTER TABLE news_articles ADD COLUMN IF NOT EXISTS source TEXT;
+ALTER TABLE news_articles ADD COLUMN IF NOT EXISTS provider TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_news_articles_provider ON news_articles (provider);
@@ -0,0 +1,12 @@
+ALTER TABLE news_articles ADD COLUMN IF NOT EXISTS category TEXT;
+
+-- an article can only have one category again, it keeps the first alphabetically
+UPDATE news_articles SET category = (
+    SELECT min(categories.name)
+    FROM article_categories
+    JOIN categories ON categories.id = article_categories.category_id
+    WHERE article_categories.article_id = news_articles.id
+);
+
+DROP TABLE IF EXISTS article_categories;
+DROP TABLE IF EXISTS categories;
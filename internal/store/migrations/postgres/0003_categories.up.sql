@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS categories
+(
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS article_categories
+(
+    article_id INTEGER NOT NULL REFERENCES news_articles (id) ON DELETE CASCADE,
+    category_id INTEGER NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    PRIMARY KEY (article_id, category_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_categories_category ON article_categories (category_id);
+
+-- the single category articles used to have moves into the join table, normalised the way new ones are
+INSERT INTO categories (name)
+SELECT DISTINCT lower(trim(category)) FROM news_articles WHERE trim(coalesce(category, '')) <> ''
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO article_categories (article_id, category_id)
+SELECT news_articles.id, categories.id
+FROM news_articles
+JOIN categories ON categories.name = lower(trim(news_articles.category))
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE news_articles DROP COLUMN IF EXISTS category;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS feed_states
+(
+    name TEXT PRIMARY KEY,
+    etag TEXT,
+    last_modified TEXT,
+    ttl_minutes INTEGER NOT NULL DEFAULT 0,
+    skip_hours TEXT,
+    skip_days TEXT,
+    last_fetched_at timestamp,
+    next_fetch_at timestamp,
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    last_success_at timestamp,
+    last_error TEXT,
+    paused BOOLEAN NOT NULL DEFAULT FALSE,
+    last_status INTEGER NOT NULL DEFAULT 0,
+    last_items_seen INTEGER NOT NULL DEFAULT 0,
+    last_items_inserted INTEGER NOT NULL DEFAULT 0,
+    last_items_updated INTEGER NOT NULL DEFAULT 0,
+    last_duplicates_skipped INTEGER NOT NULL DEFAULT 0
+);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS feeds
+(
+    name TEXT PRIMARY KEY,
+    url TEXT NOT NULL,
+    refresh_interval_seconds INTEGER NOT NULL,
+    default_category TEXT,
+    fallback_image TEXT,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at timestamp DEFAULT current_timestamp,
+    updated_at timestamp DEFAULT current_timestamp
+);
@@ -0,0 +1,5 @@
+ALTER TABLE news_articles DROP COLUMN IF EXISTS source_updated_at;
+ALTER TABLE news_articles DROP COLUMN IF EXISTS enclosures;
+ALTER TABLE news_articles DROP COLUMN IF EXISTS authors;
+ALTER TABLE news_articles DROP COLUMN IF EXISTS guid;
+ALTER TABLE news_articles DROP COLUMN IF EXISTS content;
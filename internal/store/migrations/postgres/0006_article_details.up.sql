@@ -0,0 +1,5 @@
+ALTER TABLE news_articles ADD COLUMN IF NOT EXISTS content TEXT;
+ALTER TABLE news_articles ADD COLUMN IF NOT EXISTS guid TEXT;
+ALTER TABLE news_articles ADD COLUMN IF NOT EXISTS authors JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE news_articles ADD COLUMN IF NOT EXISTS enclosures JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE news_articles ADD COLUMN IF NOT EXISTS source_updated_at timestamp;
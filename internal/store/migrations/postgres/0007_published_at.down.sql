@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_news_articles_published;
+ALTER TABLE news_articles DROP COLUMN IF EXISTS published_at;
@@ -0,0 +1,9 @@
+ALTER TABLE news_articles ADD COLUMN IF NOT EXISTS published_at timestamp;
+
+-- articles saved before the publisher's time was kept are taken to be published when they were ingested
+UPDATE news_articles SET published_at = coalesce(created_at, current_timestamp) WHERE published_at IS NULL;
+
+ALTER TABLE news_articles ALTER COLUMN published_at SET DEFAULT current_timestamp;
+ALTER TABLE news_articles ALTER COLUMN published_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_news_articles_published ON news_articles (published_at, id);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS article_revisions
+(
+    id SERIAL PRIMARY KEY,
+    article_id INTEGER NOT NULL REFERENCES news_articles (id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    description TEXT,
+    thumbnail TEXT,
+    created_at timestamp DEFAULT current_timestamp
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_revisions_article ON article_revisions (article_id);
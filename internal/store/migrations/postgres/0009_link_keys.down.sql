@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS article_sources;
+ALTER TABLE news_articles DROP COLUMN IF EXISTS link_key;
+ALTER TABLE news_articles ADD CONSTRAINT news_articles_link_key UNIQUE (link);
@@ -0,0 +1,24 @@
+ALTER TABLE news_articles ADD COLUMN IF NOT EXISTS link_key TEXT;
+
+-- links are canonicalised as articles are saved, the ones saved before then are keyed by the link as it was
+UPDATE news_articles SET link_key = link WHERE link_key IS NULL;
+
+ALTER TABLE news_articles ALTER COLUMN link_key SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_news_articles_link_key ON news_articles (link_key);
+-- the same link can be given by more than one feed, it's the key which is unique
+ALTER TABLE news_articles DROP CONSTRAINT IF EXISTS news_articles_link_key;
+
+CREATE TABLE IF NOT EXISTS article_sources
+(
+    article_id INTEGER NOT NULL REFERENCES news_articles (id) ON DELETE CASCADE,
+    source TEXT NOT NULL,
+    link_key TEXT NOT NULL,
+    created_at timestamp DEFAULT current_timestamp,
+    PRIMARY KEY (article_id, source)
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_sources_link_key ON article_sources (link_key);
+
+INSERT INTO article_sources (article_id, source, link_key, created_at)
+SELECT id, source, link_key, created_at FROM news_articles WHERE source IS NOT NULL
+ON CONFLICT DO NOTHING;
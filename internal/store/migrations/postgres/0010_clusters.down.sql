@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_news_articles_cluster;
+ALTER TABLE news_articles DROP COLUMN IF EXISTS cluster_id;
+ALTER TABLE news_articles DROP COLUMN IF EXISTS fingerprint;
@@ -0,0 +1,4 @@
+ALTER TABLE news_articles ADD COLUMN IF NOT EXISTS fingerprint BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE news_articles ADD COLUMN IF NOT EXISTS cluster_id INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_news_articles_cluster ON news_articles (cluster_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_news_articles_search;
+ALTER TABLE news_articles DROP COLUMN IF EXISTS search;
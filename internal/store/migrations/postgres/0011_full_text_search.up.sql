@@ -0,0 +1,8 @@
+-- titles are weighted above descriptions, which are above the content
+ALTER TABLE news_articles ADD COLUMN IF NOT EXISTS search tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(content, '')), 'C')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_news_articles_search ON news_articles USING GIN (search);
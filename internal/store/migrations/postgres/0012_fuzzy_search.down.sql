@@ -0,0 +1,4 @@
+-- the extension is left as something else in the database could be using it
+DROP INDEX IF EXISTS idx_categories_name_trgm;
+DROP INDEX IF EXISTS idx_news_articles_title_trgm;
+DROP INDEX IF EXISTS idx_news_articles_fuzzy;
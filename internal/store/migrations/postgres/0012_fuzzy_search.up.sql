@@ -0,0 +1,9 @@
+-- pg_trgm compares words by the sequences of three letters they share, so a word spelt slightly wrong still matches
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- for searches which don't match any words exactly
+CREATE INDEX IF NOT EXISTS idx_news_articles_fuzzy ON news_articles
+    USING GIN (lower(title || ' ' || coalesce(description, '')) gin_trgm_ops);
+-- for suggesting titles containing a word starting with what's been typed
+CREATE INDEX IF NOT EXISTS idx_news_articles_title_trgm ON news_articles USING GIN (lower(title) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_categories_name_trgm ON categories USING GIN (name gin_trgm_ops);
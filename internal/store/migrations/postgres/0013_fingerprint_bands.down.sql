@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_news_articles_fingerprint_band_7;
+DROP INDEX IF EXISTS idx_news_articles_fingerprint_band_6;
+DROP INDEX IF EXISTS idx_news_articles_fingerprint_band_5;
+DROP INDEX IF EXISTS idx_news_articles_fingerprint_band_4;
+DROP INDEX IF EXISTS idx_news_articles_fingerprint_band_3;
+DROP INDEX IF EXISTS idx_news_articles_fingerprint_band_2;
+DROP INDEX IF EXISTS idx_news_articles_fingerprint_band_1;
+DROP INDEX IF EXISTS idx_news_articles_fingerprint_band_0;
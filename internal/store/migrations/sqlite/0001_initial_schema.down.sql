@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS article_revisions;
+DROP TABLE IF EXISTS feed_states;
+DROP TABLE IF EXISTS feeds;
+DROP TABLE IF EXISTS article_categories;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS article_sources;
+DROP TRIGGER IF EXISTS news_articles_search_update;
+DROP TRIGGER IF EXISTS news_articles_search_delete;
+DROP TRIGGER IF EXISTS news_articles_search_insert;
+DROP TABLE IF EXISTS news_articles_search;
+DROP TABLE IF EXISTS news_articles;
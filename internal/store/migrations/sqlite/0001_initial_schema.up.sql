@@ -0,0 +1,127 @@
+-- AUTOINCREMENT stops the IDs of deleted articles being reused, they're used as cursors
+CREATE TABLE IF NOT EXISTS news_articles
+(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title TEXT NOT NULL,
+    description TEXT,
+    content TEXT,
+    link TEXT NOT NULL,
+    link_key TEXT NOT NULL UNIQUE,
+    guid TEXT,
+    authors TEXT NOT NULL DEFAULT '[]',
+    enclosures TEXT NOT NULL DEFAULT '[]',
+    thumbnail TEXT NOT NULL,
+    source TEXT,
+    provider TEXT,
+    published_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    source_updated_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    fingerprint INTEGER NOT NULL DEFAULT 0,
+    cluster_id INTEGER
+);
+
+CREATE INDEX IF NOT EXISTS idx_news_articles_cluster ON news_articles (cluster_id);
+CREATE INDEX IF NOT EXISTS idx_news_articles_provider ON news_articles (provider);
+CREATE INDEX IF NOT EXISTS idx_news_articles_published ON news_articles (published_at, id);
+
+-- the search index only keeps the words, the triggers keep it in step with news_articles
+CREATE VIRTUAL TABLE IF NOT EXISTS news_articles_search USING fts5
+(
+    title,
+    description,
+    content,
+    content = 'news_articles',
+    content_rowid = 'id',
+    tokenize = 'porter unicode61'
+);
+
+CREATE TRIGGER IF NOT EXISTS news_articles_search_insert AFTER INSERT ON news_articles
+BEGIN
+    INSERT INTO news_articles_search (rowid, title, description, content)
+    VALUES (new.id, new.title, new.description, new.content);
+END;
+
+CREATE TRIGGER IF NOT EXISTS news_articles_search_delete AFTER DELETE ON news_articles
+BEGIN
+    INSERT INTO news_articles_search (news_articles_search, rowid, title, description, content)
+    VALUES ('delete', old.id, old.title, old.description, old.content);
+END;
+
+CREATE TRIGGER IF NOT EXISTS news_articles_search_update AFTER UPDATE OF title, description, content ON news_articles
+BEGIN
+    INSERT INTO news_articles_search (news_articles_search, rowid, title, description, content)
+    VALUES ('delete', old.id, old.title, old.description, old.content);
+    INSERT INTO news_articles_search (rowid, title, description, content)
+    VALUES (new.id, new.title, new.description, new.content);
+END;
+
+CREATE TABLE IF NOT EXISTS article_sources
+(
+    article_id INTEGER NOT NULL REFERENCES news_articles (id) ON DELETE CASCADE,
+    source TEXT NOT NULL,
+    link_key TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (article_id, source)
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_sources_link_key ON article_sources (link_key);
+
+CREATE TABLE IF NOT EXISTS categories
+(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS article_categories
+(
+    article_id INTEGER NOT NULL REFERENCES news_articles (id) ON DELETE CASCADE,
+    category_id INTEGER NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    PRIMARY KEY (article_id, category_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_categories_category ON article_categories (category_id);
+
+CREATE TABLE IF NOT EXISTS feeds
+(
+    name TEXT PRIMARY KEY,
+    url TEXT NOT NULL,
+    refresh_interval_seconds INTEGER NOT NULL,
+    default_category TEXT,
+    fallback_image TEXT,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS feed_states
+(
+    name TEXT PRIMARY KEY,
+    etag TEXT,
+    last_modified TEXT,
+    ttl_minutes INTEGER NOT NULL DEFAULT 0,
+    skip_hours TEXT,
+    skip_days TEXT,
+    last_fetched_at TIMESTAMP,
+    next_fetch_at TIMESTAMP,
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    last_success_at TIMESTAMP,
+    last_error TEXT,
+    paused BOOLEAN NOT NULL DEFAULT FALSE,
+    last_status INTEGER NOT NULL DEFAULT 0,
+    last_items_seen INTEGER NOT NULL DEFAULT 0,
+    last_items_inserted INTEGER NOT NULL DEFAULT 0,
+    last_items_updated INTEGER NOT NULL DEFAULT 0,
+    last_duplicates_skipped INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS article_revisions
+(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    article_id INTEGER NOT NULL REFERENCES news_articles (id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    description TEXT,
+    thumbnail TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_revisions_article ON article_revisions (article_id);
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS news_articles_words_update;
+DROP TRIGGER IF EXISTS news_articles_words_delete;
+DROP TRIGGER IF EXISTS news_articles_words_insert;
+DROP TABLE IF EXISTS news_articles_words_vocab;
+DROP TABLE IF EXISTS news_articles_words;
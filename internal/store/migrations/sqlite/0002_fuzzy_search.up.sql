@@ -0,0 +1,36 @@
+-- the words of every title and description as they're written, unlike news_articles_search they aren't stemmed so a
+-- misspelt word can be compared with them
+CREATE VIRTUAL TABLE IF NOT EXISTS news_articles_words USING fts5
+(
+    title,
+    description,
+    content = 'news_articles',
+    content_rowid = 'id',
+    tokenize = 'unicode61'
+);
+
+-- every distinct word in news_articles_words
+CREATE VIRTUAL TABLE IF NOT EXISTS news_articles_words_vocab USING fts5vocab(news_articles_words, 'row');
+
+CREATE TRIGGER IF NOT EXISTS news_articles_words_insert AFTER INSERT ON news_articles
+BEGIN
+    INSERT INTO news_articles_words (rowid, title, description)
+    VALUES (new.id, new.title, new.description);
+END;
+
+CREATE TRIGGER IF NOT EXISTS news_articles_words_delete AFTER DELETE ON news_articles
+BEGIN
+    INSERT INTO news_articles_words (news_articles_words, rowid, title, description)
+    VALUES ('delete', old.id, old.title, old.description);
+END;
+
+CREATE TRIGGER IF NOT EXISTS news_articles_words_update AFTER UPDATE OF title, description ON news_articles
+BEGIN
+    INSERT INTO news_articles_words (news_articles_words, rowid, title, description)
+    VALUES ('delete', old.id, old.title, old.description);
+    INSERT INTO news_articles_words (rowid, title, description)
+    VALUES (new.id, new.title, new.description);
+END;
+
+-- index the articles stored before this migration
+INSERT INTO news_articles_words (news_articles_words) VALUES ('rebuild');
@@ -0,0 +1,10 @@
+-- articles are only compared with the articles published around the same time which have a part of their fingerprint
+-- in common, see fingerprintBands
+CREATE INDEX IF NOT EXISTS idx_news_articles_fingerprint_band_0 ON news_articles (((fingerprint >> 0) & 255), published_at);
+CREATE INDEX IF NOT EXISTS idx_news_articles_fingerprint_band_1 ON news_articles (((fingerprint >> 8) & 255), published_at);
+CREATE INDEX IF NOT EXISTS idx_news_articles_fingerprint_band_2 ON news_articles (((fingerprint >> 16) & 255), published_at);
+CREATE INDEX IF NOT EXISTS idx_news_articles_fingerprint_band_3 ON news_articles (((fingerprint >> 24) & 255), published_at);
+CREATE INDEX IF NOT EXISTS idx_news_articles_fingerprint_band_4 ON news_articles (((fingerprint >> 32) & 255), published_at);
+CREATE INDEX IF NOT EXISTS idx_news_articles_fingerprint_band_5 ON news_articles (((fingerprint >> 40) & 255), published_at);
+CREATE INDEX IF NOT EXISTS idx_news_articles_fingerprint_band_6 ON news_articles (((fingerprint >> 48) & 255), published_at);
+CREATE INDEX IF NOT EXISTS idx_news_articles_fingerprint_band_7 ON news_articles (((fingerprint >> 56) & 255), published_at);
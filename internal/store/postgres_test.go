@@ -0,0 +1,52 @@
+package store_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/moynur/news-app/internal/config"
+	"github.com/moynur/news-app/internal/store"
+	"github.com/moynur/news-app/internal/store/storetest"
+)
+
+// TestStore needs a database it can wipe, set TEST_DB_HOST (and TEST_DB_PASSWORD if it isn't postgres) to run it
+func TestStore(t *testing.T) {
+	host := os.Getenv("TEST_DB_HOST")
+	if host == "" {
+		t.Skip("TEST_DB_HOST isn't set")
+	}
+	password := os.Getenv("TEST_DB_PASSWORD")
+	if password == "" {
+		password = "postgres"
+	}
+	db, err := store.NewStore(config.DatabaseConfig{
+		Host:            host,
+		Port:            5432,
+		User:            "postgres",
+		Password:        password,
+		Name:            "postgres",
+		SSLMode:         "disable",
+		PoolSize:        5,
+		ConnMaxLifetime: time.Minute,
+		ConnectTimeout:  5 * time.Second,
+	})
+	require.NoError(t, err)
+	migrator, err := db.Migrator()
+	require.NoError(t, err)
+
+	storetest.Run(t, func(t *testing.T) store.Storer {
+		ctx := context.Background()
+		// reverting every migration and applying them again leaves an empty database
+		statuses, err := migrator.Status(ctx)
+		require.NoError(t, err)
+		_, err = migrator.Down(ctx, len(statuses))
+		require.NoError(t, err)
+		_, err = migrator.Up(ctx)
+		require.NoError(t, err)
+		return db
+	})
+}
@@ -0,0 +1,100 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ArticleChange is what happened to an article when it was saved
+type ArticleChange int
+
+const (
+	ArticleUnchanged ArticleChange = iota
+	ArticleCreated
+	ArticleUpdated
+)
+
+// UpsertResult is how many articles in a batch were inserted, updated because they changed or left as they were
+type UpsertResult struct {
+	Inserted  int
+	Updated   int
+	Unchanged int
+}
+
+func (r *UpsertResult) add(change ArticleChange) {
+	switch change {
+	case ArticleCreated:
+		r.Inserted++
+	case ArticleUpdated:
+		r.Updated++
+	default:
+		r.Unchanged++
+	}
+}
+
+// ArticleRevision is what an article looked like before it was changed, CreatedAt is when it was replaced
+type ArticleRevision struct {
+	ID          uint `gorm:"primaryKey"`
+	ArticleID   uint
+	Title       string
+	Description string
+	Thumbnail   string
+	CreatedAt   time.Time
+}
+
+// reviseArticle keeps the existing article as a revision and updates it with the request, it is put in a new cluster
+// when its fingerprint changes
+func reviseArticle(tx *gorm.DB, existing NewsArticle, request NewsArticle) error {
+	resp := tx.Create(&ArticleRevision{
+		ArticleID:   existing.ID,
+		Title:       existing.Title,
+		Description: existing.Description,
+		Thumbnail:   existing.Thumbnail,
+	})
+	if resp.Error != nil {
+		return fmt.Errorf("unable to create revision, %w", resp.Error)
+	}
+	thumbnail := request.Thumbnail
+	if thumbnail == "" {
+		thumbnail = existing.Thumbnail
+	}
+	// a map is used so a description being removed is still written
+	resp = tx.Model(&NewsArticle{}).Where("id = ?", existing.ID).Updates(map[string]interface{}{
+		"title":             request.Title,
+		"description":       request.Description,
+		"content":           request.Content,
+		"thumbnail":         thumbnail,
+		"source_updated_at": request.SourceUpdatedAt,
+		"fingerprint":       request.Fingerprint,
+	})
+	if resp.Error != nil {
+		return fmt.Errorf("unable to update article, %w", resp.Error)
+	}
+	// the article may no longer be about the same story as the rest of its cluster
+	if request.Fingerprint != existing.Fingerprint {
+		existing.Fingerprint = request.Fingerprint
+		return assignCluster(tx, &existing)
+	}
+	return nil
+}
+
+// GetArticleRevisions returns what the article looked like before each time it was changed, newest first
+func (s *Store) GetArticleRevisions(ctx context.Context, articleID int) ([]ArticleRevision, error) {
+	var found int64
+	resp := s.db.WithContext(ctx).Model(&NewsArticle{}).Where("id = ?", articleID).Count(&found)
+	if resp.Error != nil {
+		return nil, fmt.Errorf("failed to get article %w", resp.Error)
+	}
+	if found == 0 {
+		return nil, ErrNotFound
+	}
+	var revisions []ArticleRevision
+	resp = s.db.WithContext(ctx).Where("article_id = ?", articleID).Order("id desc").Find(&revisions)
+	if resp.Error != nil {
+		return nil, fmt.Errorf("failed to get revisions %w", resp.Error)
+	}
+	return revisions, nil
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	sqlitedriver "github.com/glebarez/go-sqlite"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"github.com/moynur/news-app/internal/migrate"
+)
+
+// sqlitePragmas are set on the connection. Foreign keys are off by default, LIKE would ignore case unlike Postgres and
+// the busy timeout makes other processes, like the migrate command, wait for the database rather than failing
+const sqlitePragmas = "_pragma=foreign_keys(1)&_pragma=case_sensitive_like(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)"
+
+var sqliteDialect = dialect{
+	migrations: "migrations/sqlite",
+	lock:       migrate.NoLock,
+	// there's only one connection so nothing can store the article between finding the existing articles and
+	// inserting it
+	inserted: "TRUE AS inserted",
+	duplicateKey: func(err error) bool {
+		var sqliteErr *sqlitedriver.Error
+		return errors.As(err, &sqliteErr) &&
+			(sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY)
+	},
+	match: func(words []string, phrase bool) clause.Expr {
+		query := matchQuery(words, " ")
+		if phrase {
+			query = `"` + strings.Join(words, " ") + `"`
+		}
+		return gorm.Expr("news_articles.id IN (SELECT rowid FROM news_articles_search WHERE news_articles_search MATCH ?)", query)
+	},
+	// bm25 is lower for a better match, it's only available in a query which is matching the index
+	rank: func(words []string) clause.Expr {
+		return gorm.Expr("-(SELECT bm25(news_articles_search, 10.0, 5.0, 1.0) FROM news_articles_search "+
+			"WHERE news_articles_search MATCH ? AND rowid = news_articles.id)", matchQuery(words, " OR "))
+	},
+	headline: func(words []string) clause.Expr {
+		snippet := func(column int) string {
+			return fmt.Sprintf("snippet(news_articles_search, %d, '%s', '%s', '…', 32)", column, highlightStart, highlightStop)
+		}
+		return gorm.Expr("(SELECT CASE WHEN news_articles.description <> '' THEN "+snippet(1)+" ELSE "+snippet(0)+" END "+
+			"FROM news_articles_search WHERE news_articles_search MATCH ? AND rowid = news_articles.id)", matchQuery(words, " OR "))
+	},
+	fuzzyMatch: func(words []string) clause.Expr {
+		return gorm.Expr("news_articles.id IN (SELECT rowid FROM news_articles_words WHERE news_articles_words MATCH ?)",
+			similarWordsQuery(words, " AND "))
+	},
+	fuzzyRank: func(words []string) clause.Expr {
+		return gorm.Expr("-(SELECT bm25(news_articles_words, 2.0, 1.0) FROM news_articles_words "+
+			"WHERE news_articles_words MATCH ? AND rowid = news_articles.id)", similarWordsQuery(words, " OR "))
+	},
+}
+
+// maxSimilarWords is how many of the indexed words spelt like each word of a fuzzy search are matched
+const maxSimilarWords = 20
+
+// similarWordsQuery builds an FTS5 query matching any of the indexed words spelt similarly to each of the words, the
+// groups for each word joined by the operator. SQLite can't compare words itself so a word is similar when it has
+// enough of the trigrams of the word searched for, counted with instr
+func similarWordsQuery(words []string, operator string) clause.Expr {
+	groups := make([]string, len(words))
+	var vars []interface{}
+	for i, word := range words {
+		wordTrigrams := trigrams(word)
+		shared := make([]string, len(wordTrigrams))
+		for j, trigram := range wordTrigrams {
+			shared[j] = "(instr('  ' || term || ' ', ?) > 0)"
+			vars = append(vars, trigram)
+		}
+		// when nothing is similar the word is searched for as it is, which won't match anything either
+		groups[i] = "'(' || coalesce((SELECT group_concat('\"' || term || '\"', ' OR ') FROM (" +
+			"SELECT term FROM (SELECT term, " + strings.Join(shared, " + ") + " AS shared FROM news_articles_words_vocab) " +
+			"WHERE shared >= ? ORDER BY shared DESC, term LIMIT ?)), ?) || ')'"
+		vars = append(vars, math.Ceil(similarityThreshold*float64(len(wordTrigrams))), maxSimilarWords, `"`+word+`"`)
+	}
+	return gorm.Expr(strings.Join(groups, " || '"+operator+"' || "), vars...)
+}
+
+// matchQuery is an FTS5 query for the words joined by the operator, a space means they all have to match. Quoting each
+// word stops it being read as FTS5 syntax, words are only letters and numbers so they can't contain a quote
+func matchQuery(words []string, operator string) string {
+	terms := make([]string, len(words))
+	for i, word := range words {
+		terms[i] = `"` + word + `"`
+	}
+	return strings.Join(terms, operator)
+}
+
+// NewSQLiteStore opens the SQLite database at the path, creating it when it doesn't exist. It's for running on a single
+// node, the same process has to be the only one writing to the database
+func NewSQLiteStore(path string) (*Store, error) {
+	log.Println("opening sqlite database", path)
+	db, err := gorm.Open(sqlite.Open(path+"?"+sqlitePragmas), &gorm.Config{
+		// times are compared as text so they all have to be in the same zone
+		NowFunc: func() time.Time {
+			return time.Now().UTC()
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to open database, %w", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get database connection pool, %w", err)
+	}
+	// SQLite only has one writer at a time, a single connection queues the writes rather than them failing as busy
+	sqlDB.SetMaxOpenConns(1)
+	return &Store{
+		db:      db,
+		dialect: sqliteDialect,
+	}, nil
+}
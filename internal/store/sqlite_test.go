@@ -0,0 +1,38 @@
+package store_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/moynur/news-app/internal/store"
+	"github.com/moynur/news-app/internal/store/storetest"
+)
+
+func TestSQLiteStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Storer {
+		db, err := store.NewSQLiteStore(filepath.Join(t.TempDir(), "news.db"))
+		require.NoError(t, err)
+		migrator, err := db.Migrator()
+		require.NoError(t, err)
+		_, err = migrator.Up(context.Background())
+		require.NoError(t, err)
+		return db
+	})
+}
+
+func TestSQLiteStore_MigrateDown(t *testing.T) {
+	ctx := context.Background()
+	db, err := store.NewSQLiteStore(filepath.Join(t.TempDir(), "news.db"))
+	require.NoError(t, err)
+	migrator, err := db.Migrator()
+	require.NoError(t, err)
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	_, err = migrator.Down(ctx, len(applied))
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err, "the down migrations should leave nothing behind")
+}
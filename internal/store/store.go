@@ -2,112 +2,667 @@
 package store
 
 import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
-	"github.com/jackc/pgconn"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/moynur/news-app/internal/config"
 )
 
 const ErrDuplicateKey = "23505"
 
+// upsertBatchSize keeps each insert well under Postgres' limit on the number of parameters in a statement
+const upsertBatchSize = 100
+
+var (
+	ErrNotFound      = errors.New("record not found")
+	ErrAlreadyExists = errors.New("record already exists")
+)
+
 type Storer interface {
-	CreateArticleIfNotExists(request NewsArticle) error
-	GetRecordsAfterID(ID int, numberOfRecords int, filters Filters) ([]NewsArticle, error)
+	// UpsertArticles reports how many of the articles were inserted, updated because they changed or left as they were
+	UpsertArticles(ctx context.Context, articles []NewsArticle) (UpsertResult, error)
+	GetArticleRevisions(ctx context.Context, articleID int) ([]ArticleRevision, error)
+	GetRecordsAfterID(ctx context.Context, ID int, numberOfRecords int, filters Filters) ([]NewsArticle, error)
+	Suggest(ctx context.Context, prefix string, limit int) ([]Suggestion, error)
+	GetFeeds(ctx context.Context) ([]Feed, error)
+	GetFeed(ctx context.Context, name string) (Feed, error)
+	CreateFeed(ctx context.Context, feed Feed) error
+	CreateFeedsIfNotExist(ctx context.Context, feeds []Feed) error
+	UpdateFeed(ctx context.Context, feed Feed) error
+	DeleteFeed(ctx context.Context, name string) error
+	GetFeedState(ctx context.Context, name string) (FeedState, error)
+	GetFeedStates(ctx context.Context) ([]FeedState, error)
+	SaveFeedState(ctx context.Context, state FeedState) error
+	ResumeFeed(ctx context.Context, name string) error
 }
 
 type Store struct {
-	db *gorm.DB
+	db      *gorm.DB
+	dialect dialect
 }
 
 type NewsArticle struct {
 	ID          uint `gorm:"primaryKey"`
 	Title       string
 	Description string
-	Link        string `gorm:"unique_index:idx_link"`
-	Category    string
-	Thumbnail   string
-	CreatedAt   time.Time
+	// Content is the full body of the article when the publisher includes it
+	Content string
+	// Link is the article's page, GUID is whatever the publisher uses to identify it which isn't always a url
+	Link string
+	// LinkKey is the canonical link without its scheme, articles are de-duplicated on it
+	LinkKey    string `gorm:"unique_index:idx_link_key"`
+	GUID       string `gorm:"column:guid"`
+	Authors    StringList
+	Enclosures Enclosures
+	Categories []Category `gorm:"many2many:article_categories;joinForeignKey:ArticleID;joinReferences:CategoryID"`
+	// Source is the feed the article was first seen in, Sources are every feed it has been seen in
+	Source    string
+	Sources   []ArticleSource `gorm:"foreignKey:ArticleID"`
+	Provider  string
+	Thumbnail string
+	// PublishedAt is when the publisher says the article was published, falling back to when it was ingested when the
+	// feed doesn't say. SourceUpdatedAt is when the publisher last changed it
+	PublishedAt     time.Time
+	SourceUpdatedAt *time.Time
+	// CreatedAt is when the article was ingested
+	CreatedAt time.Time
+	// Fingerprint is a SimHash of the title and description, articles with similar fingerprints are put in the same
+	// cluster as they're about the same story. ClusterID is the ID of the first article about the story
+	Fingerprint int64
+	ClusterID   uint
+	// Related is how many other articles are in the cluster, it is only counted when collapsing clusters
+	Related int `gorm:"-"`
+	// Inserted is only read back when upserting, it's false when the article had already been stored
+	Inserted bool `gorm:"->"`
+	// Headline is only read back when searching, it's the description with the words which matched highlighted
+	Headline string `gorm:"->"`
+}
+
+// Enclosure is a file attached to an article such as an image, audio or video
+type Enclosure struct {
+	URL    string `json:"url"`
+	Type   string `json:"type,omitempty"`
+	Length int64  `json:"length,omitempty"`
+}
+
+// StringList is stored as a json array
+type StringList []string
+
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	return jsonValue(l)
+}
+
+func (l *StringList) Scan(value interface{}) error {
+	return scanJSON(value, l)
+}
+
+// Enclosures is stored as a json array
+type Enclosures []Enclosure
+
+func (e Enclosures) Value() (driver.Value, error) {
+	if e == nil {
+		return "[]", nil
+	}
+	return jsonValue(e)
+}
+
+func (e *Enclosures) Scan(value interface{}) error {
+	return scanJSON(value, e)
+}
+
+func jsonValue(value interface{}) (driver.Value, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode json column, %w", err)
+	}
+	return string(b), nil
+}
+
+func scanJSON(value interface{}, dest interface{}) error {
+	var b []byte
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("unable to decode json column from %T", value)
+	}
+	if err := json.Unmarshal(b, dest); err != nil {
+		return fmt.Errorf("unable to decode json column, %w", err)
+	}
+	return nil
+}
+
+type Category struct {
+	ID   uint `gorm:"primaryKey"`
+	Name string
+}
+
+type ArticleCategory struct {
+	ArticleID  uint
+	CategoryID uint
 }
 
+// SortOrder is which time articles are ordered by, records with the same time are ordered by ID
+type SortOrder string
+
+const (
+	// SortIngested orders by when the articles were ingested which is the same as ordering by ID
+	SortIngested SortOrder = ""
+	// SortPublished orders by when the publisher says the articles were published
+	SortPublished SortOrder = "published"
+	// SortRelevance orders the best matches for the search first, without any words to search for it's the same as
+	// SortIngested
+	SortRelevance SortOrder = "relevance"
+)
+
 type Filters struct {
-	Title         string
-	Description   string
-	Link          string `gorm:"unique_index:idx_link"`
-	Category      string
-	CreatedAfter  *time.Time
-	CreatedBefore *time.Time
-}
-
-func NewStore() (*Store, error) {
-	dsn := "host=db user=postgres password=postgres dbname=postgres port=5432 sslmode=disable"
-	log.Println(dsn)
-	db, err := gorm.Open(postgres.Open(dsn))
+	Title       string
+	Description string
+	Link        string `gorm:"unique_index:idx_link"`
+	Categories  []string
+	// MatchAllCategories only returns articles in every one of the categories rather than any of them
+	MatchAllCategories bool
+	Provider           string
+	CreatedAfter       *time.Time
+	CreatedBefore      *time.Time
+	PublishedAfter     *time.Time
+	PublishedBefore    *time.Time
+	// Sort isn't a filter but it decides which records come after the cursor, Descending reverses it so the latest
+	// are first. The best matches always come first when sorting by relevance
+	Sort       SortOrder
+	Descending bool
+	// Before returns the records which come before the cursor rather than after it, the ones closest to it. They're
+	// still in the sort order
+	Before bool
+	// CollapseClusters only returns the first article of each story, with how many other articles there are about it
+	CollapseClusters bool
+	// Condition only returns the articles it's true for
+	Condition *Condition
+	// Fuzzy matches the text in Condition with words spelt similarly in the title or
+	// description, for when searching for them as they are finds nothing
+	Fuzzy bool
+}
+
+// NewStore connects to the database, it fails when the database can't be reached rather than on the first query
+func NewStore(cfg config.DatabaseConfig) (*Store, error) {
+	log.Println("connecting to database", cfg.Host, cfg.Port, cfg.Name)
+	db, err := gorm.Open(postgres.Open(cfg.DSN()))
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to database, %w", err)
+	}
+	sqlDB, err := db.DB()
 	if err != nil {
-		log.Println(err)
+		return nil, fmt.Errorf("unable to get database connection pool, %w", err)
 	}
+	sqlDB.SetMaxOpenConns(cfg.PoolSize)
+	sqlDB.SetMaxIdleConns(cfg.PoolSize)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 	return &Store{
-		db: db,
+		db:      db,
+		dialect: postgresDialect,
 	}, nil
 }
 
-func (s *Store) CreateArticleIfNotExists(request NewsArticle) error {
-	// there's probably a way to leverage FirstOrCreate instead of using db schemas to do this
-	log.Println("store request", &request.Title)
-	log.Println("creating record as not found")
-	result := s.db.Create(&request)
-	if result.Error != nil {
-		var pgErr *pgconn.PgError
-		// bit of a hack because of the above comment and this error isn't available from gorm as standard
-		if errors.As(result.Error, &pgErr) && pgErr.Code == ErrDuplicateKey {
-			log.Println("record already exists")
-			return nil
+func (ArticleCategory) TableName() string {
+	return "article_categories"
+}
+
+// ArticleSource is a feed an article has been seen in, LinkKey is the key of the link that feed gave it which can be
+// different to the article's when the article's page says another link is canonical
+type ArticleSource struct {
+	ArticleID uint   `gorm:"primaryKey"`
+	Source    string `gorm:"primaryKey"`
+	LinkKey   string
+	CreatedAt time.Time
+}
+
+// NormaliseCategory makes sure the same category from different feeds is only stored once
+func NormaliseCategory(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// UpsertArticles saves a batch of articles. Articles whose link hasn't been seen before are inserted, the rest are
+// updated when the title, description or image have changed keeping what they were before as a revision. An empty
+// thumbnail means no image was found so the article keeps the one it has, a new article is given its feed's fallback
+// image instead
+func (s *Store) UpsertArticles(ctx context.Context, articles []NewsArticle) (UpsertResult, error) {
+	log.Println("store request", len(articles))
+	var result UpsertResult
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result = UpsertResult{}
+		articles = uniqueArticles(articles, &result)
+		existing, err := findExisting(tx, articles)
+		if err != nil {
+			return err
+		}
+		var created []NewsArticle
+		var sources []ArticleSource
+		for _, request := range articles {
+			found, ok := existing.match(request)
+			if !ok {
+				created = append(created, request)
+				continue
+			}
+			sources = append(sources, articleSources(found.ID, request.Sources)...)
+			change, err := saveExisting(tx, *found, request)
+			if err != nil {
+				return err
+			}
+			result.add(change)
+		}
+		err = addSources(tx, sources)
+		if err != nil {
+			return err
+		}
+		return insertArticles(tx, created, s.dialect.inserted, &result)
+	})
+	if err != nil {
+		return UpsertResult{}, fmt.Errorf("unable to save records, %w", err)
+	}
+	return result, nil
+}
+
+// uniqueArticles drops articles which have the same link as an earlier one in the batch, they're counted as unchanged.
+// The times of the rest are put in UTC
+func uniqueArticles(articles []NewsArticle, result *UpsertResult) []NewsArticle {
+	seen := make(map[string]bool, len(articles))
+	unique := make([]NewsArticle, 0, len(articles))
+	for _, article := range articles {
+		if seen[article.LinkKey] {
+			result.Unchanged++
+			continue
+		}
+		seen[article.LinkKey] = true
+		// the columns don't keep the zone, so every time is stored in UTC
+		article.PublishedAt = article.PublishedAt.UTC()
+		if article.SourceUpdatedAt != nil {
+			updated := article.SourceUpdatedAt.UTC()
+			article.SourceUpdatedAt = &updated
+		}
+		unique = append(unique, article)
+	}
+	return unique
+}
+
+// existingArticles are stored articles by every key they're known by
+type existingArticles map[string]*NewsArticle
+
+func (e existingArticles) match(request NewsArticle) (*NewsArticle, bool) {
+	for _, key := range linkKeys(request) {
+		if found, ok := e[key]; ok {
+			return found, true
+		}
+	}
+	return nil, false
+}
+
+// findExisting locks the articles in the batch which have already been stored
+func findExisting(tx *gorm.DB, articles []NewsArticle) (existingArticles, error) {
+	existing := existingArticles{}
+	var keys []string
+	for _, article := range articles {
+		keys = append(keys, linkKeys(article)...)
+	}
+	if len(keys) == 0 {
+		return existing, nil
+	}
+	var found []NewsArticle
+	resp := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("link_key IN ?", keys).
+		Or("id IN (?)", tx.Model(&ArticleSource{}).Select("article_id").Where("link_key IN ?", keys)).
+		Order("id").Find(&found)
+	if resp.Error != nil {
+		return nil, fmt.Errorf("unable to find existing articles, %w", resp.Error)
+	}
+	if len(found) == 0 {
+		return existing, nil
+	}
+	byID := make(map[uint]*NewsArticle, len(found))
+	for i := range found {
+		existing[found[i].LinkKey] = &found[i]
+		byID[found[i].ID] = &found[i]
+	}
+	var sources []ArticleSource
+	resp = tx.Where("article_id IN ?", keysOf(byID)).Find(&sources)
+	if resp.Error != nil {
+		return nil, fmt.Errorf("unable to find existing sources, %w", resp.Error)
+	}
+	for _, source := range sources {
+		if _, ok := existing[source.LinkKey]; !ok {
+			existing[source.LinkKey] = byID[source.ArticleID]
+		}
+	}
+	return existing, nil
+}
+
+func keysOf(articles map[uint]*NewsArticle) []uint {
+	ids := make([]uint, 0, len(articles))
+	for id := range articles {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// saveExisting revises the article when it has changed, the request's feeds are recorded against it along with the
+// rest of the batch's
+func saveExisting(tx *gorm.DB, existing NewsArticle, request NewsArticle) (ArticleChange, error) {
+	if !changed(existing, request) {
+		return ArticleUnchanged, nil
+	}
+	err := reviseArticle(tx, existing, request)
+	if err != nil {
+		return ArticleUnchanged, err
+	}
+	return ArticleUpdated, nil
+}
+
+// insertArticles inserts new articles in batches. Another feed can store the same link between finding the existing
+// articles and inserting, the conflict makes the insert return the stored article so it's compared like any other.
+// The feeds each batch has been seen in are recorded together once the batch is inserted
+func insertArticles(tx *gorm.DB, articles []NewsArticle, inserted string, result *UpsertResult) error {
+	if len(articles) == 0 {
+		return nil
+	}
+	err := fallbackThumbnails(tx, articles)
+	if err != nil {
+		return err
+	}
+	for start := 0; start < len(articles); start += upsertBatchSize {
+		end := start + upsertBatchSize
+		if end > len(articles) {
+			end = len(articles)
+		}
+		batch := articles[start:end]
+		resp := tx.Omit("Categories", "Sources").Clauses(
+			// updating the key on conflict means the existing ID is always returned
+			clause.OnConflict{
+				Columns:   []clause.Column{{Name: "link_key"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{"link_key": gorm.Expr("EXCLUDED.link_key")}),
+			},
+			clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: inserted, Raw: true}}},
+		).Create(&batch)
+		if resp.Error != nil {
+			return fmt.Errorf("unable to insert articles, %w", resp.Error)
+		}
+		var sources []ArticleSource
+		for i := range batch {
+			sources = append(sources, articleSources(batch[i].ID, batch[i].Sources)...)
+			change, err := savedArticle(tx, &batch[i])
+			if err != nil {
+				return err
+			}
+			result.add(change)
+		}
+		err = addSources(tx, sources)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// savedArticle finishes off an article which has just been inserted, or compares it with the stored article when
+// another feed beat it to it
+func savedArticle(tx *gorm.DB, article *NewsArticle) (ArticleChange, error) {
+	if !article.Inserted {
+		var existing NewsArticle
+		resp := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&existing, article.ID)
+		if resp.Error != nil {
+			return ArticleUnchanged, fmt.Errorf("unable to find existing article, %w", resp.Error)
+		}
+		return saveExisting(tx, existing, *article)
+	}
+	err := assignCluster(tx, article)
+	if err != nil {
+		return ArticleUnchanged, err
+	}
+	err = linkCategories(tx, article.ID, article.Categories)
+	if err != nil {
+		return ArticleUnchanged, err
+	}
+	return ArticleCreated, nil
+}
+
+// fallbackThumbnails gives articles without an image their feed's fallback image
+func fallbackThumbnails(tx *gorm.DB, articles []NewsArticle) error {
+	var names []string
+	for _, article := range articles {
+		if article.Thumbnail == "" {
+			names = append(names, article.Source)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	var feeds []Feed
+	resp := tx.Select("name", "fallback_image").Where("name IN ?", names).Find(&feeds)
+	if resp.Error != nil {
+		return fmt.Errorf("unable to get fallback images, %w", resp.Error)
+	}
+	fallbacks := make(map[string]string, len(feeds))
+	for _, feed := range feeds {
+		fallbacks[feed.Name] = feed.FallbackImage
+	}
+	for i := range articles {
+		if articles[i].Thumbnail == "" {
+			articles[i].Thumbnail = fallbacks[articles[i].Source]
+		}
+	}
+	return nil
+}
+
+// linkKeys are the keys the article could already have been stored under, its own and the ones the feeds gave it
+func linkKeys(article NewsArticle) []string {
+	keys := []string{article.LinkKey}
+	for _, source := range article.Sources {
+		if source.LinkKey != "" && source.LinkKey != article.LinkKey {
+			keys = append(keys, source.LinkKey)
+		}
+	}
+	return keys
+}
+
+// articleSources are the feeds the article has been seen in ready to be recorded against it
+func articleSources(articleID uint, sources []ArticleSource) []ArticleSource {
+	found := make([]ArticleSource, 0, len(sources))
+	for _, source := range sources {
+		source.ArticleID = articleID
+		found = append(found, source)
+	}
+	return found
+}
+
+// addSources records the feeds a batch of articles have been seen in, a feed which has been recorded against an
+// article before is left as it is
+func addSources(tx *gorm.DB, sources []ArticleSource) error {
+	if len(sources) == 0 {
+		return nil
+	}
+	resp := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&sources, upsertBatchSize)
+	if resp.Error != nil {
+		return fmt.Errorf("unable to record sources, %w", resp.Error)
+	}
+	return nil
+}
+
+// changed only looks at what readers see in the list of articles, an empty thumbnail means no image was found
+func changed(existing NewsArticle, request NewsArticle) bool {
+	return existing.Title != request.Title ||
+		existing.Description != request.Description ||
+		(request.Thumbnail != "" && existing.Thumbnail != request.Thumbnail)
+}
+
+// linkCategories creates any categories which haven't been seen before and links all of them to the article
+func linkCategories(tx *gorm.DB, articleID uint, categories []Category) error {
+	for _, category := range categories {
+		category.Name = NormaliseCategory(category.Name)
+		if category.Name == "" {
+			continue
+		}
+		// updating the name on conflict means the existing ID is always returned
+		result := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name"}),
+		}).Create(&category)
+		if result.Error != nil {
+			return fmt.Errorf("unable to create category %s, %w", category.Name, result.Error)
+		}
+		result = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&ArticleCategory{
+			ArticleID:  articleID,
+			CategoryID: category.ID,
+		})
+		if result.Error != nil {
+			return fmt.Errorf("unable to link category %s, %w", category.Name, result.Error)
 		}
-		return fmt.Errorf("unable to create record, %w", result.Error)
 	}
 	return nil
 }
 
-// GetRecordsAfterID returns all matching records which have an ID larger than the one provided, within the limit
-// that pass the filters, it will also order them with the ID ascending so the highest ID will be last in the array
-func (s *Store) GetRecordsAfterID(ID int, numberOfRecords int, filters Filters) ([]NewsArticle, error) {
+// GetRecordsAfterID returns all matching records which come after the record with the ID provided, within the limit
+// that pass the filters. By default they're ordered with the ID ascending so the highest ID will be last in the array,
+// when sorting by published time they're ordered by published time and then ID so the cursor is still just an ID.
+// Sorting by relevance works the same way, the best match first and then by ID
+func (s *Store) GetRecordsAfterID(ctx context.Context, ID int, numberOfRecords int, filters Filters) ([]NewsArticle, error) {
 	log.Println("get store request", ID, numberOfRecords, filters)
 	var FindResult []NewsArticle
-	resp := s.db.Where("ID > ?", ID).Order("ID asc").Limit(numberOfRecords)
+	resp := s.db.WithContext(ctx).Preload("Categories").Preload("Sources").Limit(numberOfRecords)
+	words := rankWords(filters)
+	// a fuzzy search doesn't know which words matched to highlight them
+	if len(words) > 0 && !filters.Fuzzy {
+		resp = resp.Select("news_articles.*, ? AS headline", s.dialect.headline(words))
+	}
+	// keys are what the records are sorted by, with the ID last so no two are the same. A record comes after the
+	// cursor in the order the records are read when its keys are greater, or less when reading them in descending order
+	keys, vars := "news_articles.id", []interface{}{}
+	cursorKeys := "?"
+	descending := filters.Descending
+	switch {
+	case filters.Sort == SortPublished:
+		keys, cursorKeys = "news_articles.published_at, news_articles.id", "SELECT published_at, id FROM news_articles WHERE id = ?"
+	case filters.Sort == SortRelevance && len(words) > 0:
+		rank := s.dialect.rank(words)
+		if filters.Fuzzy {
+			rank = s.dialect.fuzzyRank(words)
+		}
+		// the rank is negated so a worse match and a later ID both compare as greater
+		keys, vars = "-(?), news_articles.id", []interface{}{rank}
+		cursorKeys = "SELECT -(?), id FROM news_articles WHERE id = ?"
+		descending = false
+	}
+	// records before the cursor are read backwards from it so the closest ones are found, then put back in order
+	if filters.Before {
+		descending = !descending
+	}
+	operator, direction := ">", " ASC"
+	if descending {
+		operator, direction = "<", " DESC"
+	}
+	var order []string
+	for _, key := range strings.Split(keys, ", ") {
+		order = append(order, key+direction)
+	}
+	resp = resp.Clauses(clause.OrderBy{Expression: gorm.Expr(strings.Join(order, ", "), vars...)})
+	if ID > 0 {
+		resp = resp.Where("("+keys+") "+operator+" ("+cursorKeys+")", append(append(vars, vars...), ID)...)
+	}
+
+	resp = s.applyFilters(resp, filters)
+	if filters.CollapseClusters {
+		// an article is only returned when it's the first in its cluster to match the filters
+		matching := s.applyFilters(s.db.Model(&NewsArticle{}).Select("id"), filters)
+		resp = resp.Where("NOT EXISTS (?)", s.db.Table("news_articles AS other").Select("1").
+			Where("other.cluster_id = news_articles.cluster_id AND other.id < news_articles.id AND other.id IN (?)", matching))
+	}
+
+	resp = resp.Find(&FindResult)
+	if resp.Error != nil {
+		return []NewsArticle{}, fmt.Errorf("failed to get record %e", resp.Error)
+	}
+	if filters.Before {
+		reverseArticles(FindResult)
+	}
+	if filters.CollapseClusters {
+		err := s.countRelated(ctx, FindResult)
+		if err != nil {
+			return []NewsArticle{}, err
+		}
+	}
+	log.Println("find result", FindResult)
+	return FindResult, nil
+}
 
+func reverseArticles(articles []NewsArticle) {
+	for i, j := 0, len(articles)-1; i < j; i, j = i+1, j-1 {
+		articles[i], articles[j] = articles[j], articles[i]
+	}
+}
+
+func (s *Store) applyFilters(query *gorm.DB, filters Filters) *gorm.DB {
 	if filters.Title != "" {
-		resp = resp.Where("title LIKE ?", filters.Title)
+		query = query.Where("title LIKE ?", filters.Title)
 	}
 
 	if filters.Description != "" {
-		resp = resp.Where("description LIKE ?", filters.Title)
+		query = query.Where("description LIKE ?", filters.Description)
 	}
 
 	if filters.Link != "" {
-		resp = resp.Where("link LIKE ?", filters.Title)
+		query = query.Where("link LIKE ?", filters.Link)
 	}
 
-	if filters.Category != "" {
-		resp = resp.Where("category = ?", filters.Title)
+	if len(filters.Categories) > 0 {
+		categories := s.db.Table("article_categories").
+			Select("article_categories.article_id").
+			Joins("JOIN categories ON categories.id = article_categories.category_id").
+			Where("categories.name IN ?", filters.Categories)
+		if filters.MatchAllCategories {
+			categories = categories.Group("article_categories.article_id").
+				Having("COUNT(DISTINCT categories.name) = ?", len(filters.Categories))
+		}
+		query = query.Where("id IN (?)", categories)
 	}
 
-	if filters.CreatedAfter != nil {
-		resp = resp.Where("created_at > ?", filters.CreatedAfter)
+	if filters.Provider != "" {
+		query = query.Where("provider = ?", filters.Provider)
 	}
 
 	if filters.CreatedAfter != nil {
-		resp = resp.Where("created_at < ?", filters.CreatedBefore)
+		query = query.Where("created_at > ?", filters.CreatedAfter.UTC())
 	}
 
-	resp = resp.Find(&FindResult)
-	if resp.Error != nil {
-		return []NewsArticle{}, fmt.Errorf("failed to get record %e", resp.Error)
+	if filters.CreatedBefore != nil {
+		query = query.Where("created_at < ?", filters.CreatedBefore.UTC())
 	}
-	log.Println("find result", FindResult)
-	return FindResult, nil
+
+	if filters.PublishedAfter != nil {
+		query = query.Where("published_at > ?", filters.PublishedAfter.UTC())
+	}
+
+	if filters.PublishedBefore != nil {
+		query = query.Where("published_at < ?", filters.PublishedBefore.UTC())
+	}
+
+	if filters.Condition != nil {
+		query = query.Where(s.condition(*filters.Condition, filters.Fuzzy))
+	}
+	return query
 }
 
 // Could use this method to fetch data around a singular page which could later be passed to a template to return HTML
@@ -5,6 +5,7 @@
 package store
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -33,31 +34,206 @@ func (m *MockStorer) EXPECT() *MockStorerMockRecorder {
 	return m.recorder
 }
 
-// CreateArticleIfNotExists mocks base method.
-func (m *MockStorer) CreateArticleIfNotExists(request NewsArticle) error {
+// CreateFeed mocks base method.
+func (m *MockStorer) CreateFeed(ctx context.Context, feed Feed) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateArticleIfNotExists", request)
+	ret := m.ctrl.Call(m, "CreateFeed", ctx, feed)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CreateArticleIfNotExists indicates an expected call of CreateArticleIfNotExists.
-func (mr *MockStorerMockRecorder) CreateArticleIfNotExists(request interface{}) *gomock.Call {
+// CreateFeed indicates an expected call of CreateFeed.
+func (mr *MockStorerMockRecorder) CreateFeed(ctx, feed interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateArticleIfNotExists", reflect.TypeOf((*MockStorer)(nil).CreateArticleIfNotExists), request)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFeed", reflect.TypeOf((*MockStorer)(nil).CreateFeed), ctx, feed)
+}
+
+// CreateFeedsIfNotExist mocks base method.
+func (m *MockStorer) CreateFeedsIfNotExist(ctx context.Context, feeds []Feed) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFeedsIfNotExist", ctx, feeds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateFeedsIfNotExist indicates an expected call of CreateFeedsIfNotExist.
+func (mr *MockStorerMockRecorder) CreateFeedsIfNotExist(ctx, feeds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFeedsIfNotExist", reflect.TypeOf((*MockStorer)(nil).CreateFeedsIfNotExist), ctx, feeds)
+}
+
+// DeleteFeed mocks base method.
+func (m *MockStorer) DeleteFeed(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFeed", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFeed indicates an expected call of DeleteFeed.
+func (mr *MockStorerMockRecorder) DeleteFeed(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFeed", reflect.TypeOf((*MockStorer)(nil).DeleteFeed), ctx, name)
+}
+
+// GetArticleRevisions mocks base method.
+func (m *MockStorer) GetArticleRevisions(ctx context.Context, articleID int) ([]ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArticleRevisions", ctx, articleID)
+	ret0, _ := ret[0].([]ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArticleRevisions indicates an expected call of GetArticleRevisions.
+func (mr *MockStorerMockRecorder) GetArticleRevisions(ctx, articleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArticleRevisions", reflect.TypeOf((*MockStorer)(nil).GetArticleRevisions), ctx, articleID)
+}
+
+// GetFeed mocks base method.
+func (m *MockStorer) GetFeed(ctx context.Context, name string) (Feed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeed", ctx, name)
+	ret0, _ := ret[0].(Feed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeed indicates an expected call of GetFeed.
+func (mr *MockStorerMockRecorder) GetFeed(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeed", reflect.TypeOf((*MockStorer)(nil).GetFeed), ctx, name)
+}
+
+// GetFeedState mocks base method.
+func (m *MockStorer) GetFeedState(ctx context.Context, name string) (FeedState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeedState", ctx, name)
+	ret0, _ := ret[0].(FeedState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeedState indicates an expected call of GetFeedState.
+func (mr *MockStorerMockRecorder) GetFeedState(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeedState", reflect.TypeOf((*MockStorer)(nil).GetFeedState), ctx, name)
+}
+
+// GetFeedStates mocks base method.
+func (m *MockStorer) GetFeedStates(ctx context.Context) ([]FeedState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeedStates", ctx)
+	ret0, _ := ret[0].([]FeedState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeedStates indicates an expected call of GetFeedStates.
+func (mr *MockStorerMockRecorder) GetFeedStates(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeedStates", reflect.TypeOf((*MockStorer)(nil).GetFeedStates), ctx)
+}
+
+// GetFeeds mocks base method.
+func (m *MockStorer) GetFeeds(ctx context.Context) ([]Feed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeeds", ctx)
+	ret0, _ := ret[0].([]Feed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeeds indicates an expected call of GetFeeds.
+func (mr *MockStorerMockRecorder) GetFeeds(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeeds", reflect.TypeOf((*MockStorer)(nil).GetFeeds), ctx)
 }
 
 // GetRecordsAfterID mocks base method.
-func (m *MockStorer) GetRecordsAfterID(ID, numberOfRecords int, filters Filters) ([]NewsArticle, error) {
+func (m *MockStorer) GetRecordsAfterID(ctx context.Context, ID, numberOfRecords int, filters Filters) ([]NewsArticle, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRecordsAfterID", ID, numberOfRecords, filters)
+	ret := m.ctrl.Call(m, "GetRecordsAfterID", ctx, ID, numberOfRecords, filters)
 	ret0, _ := ret[0].([]NewsArticle)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetRecordsAfterID indicates an expected call of GetRecordsAfterID.
-func (mr *MockStorerMockRecorder) GetRecordsAfterID(ID, numberOfRecords, filters interface{}) *gomock.Call {
+func (mr *MockStorerMockRecorder) GetRecordsAfterID(ctx, ID, numberOfRecords, filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecordsAfterID", reflect.TypeOf((*MockStorer)(nil).GetRecordsAfterID), ctx, ID, numberOfRecords, filters)
+}
+
+// ResumeFeed mocks base method.
+func (m *MockStorer) ResumeFeed(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeFeed", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResumeFeed indicates an expected call of ResumeFeed.
+func (mr *MockStorerMockRecorder) ResumeFeed(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeFeed", reflect.TypeOf((*MockStorer)(nil).ResumeFeed), ctx, name)
+}
+
+// SaveFeedState mocks base method.
+func (m *MockStorer) SaveFeedState(ctx context.Context, state FeedState) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveFeedState", ctx, state)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveFeedState indicates an expected call of SaveFeedState.
+func (mr *MockStorerMockRecorder) SaveFeedState(ctx, state interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveFeedState", reflect.TypeOf((*MockStorer)(nil).SaveFeedState), ctx, state)
+}
+
+// Suggest mocks base method.
+func (m *MockStorer) Suggest(ctx context.Context, prefix string, limit int) ([]Suggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Suggest", ctx, prefix, limit)
+	ret0, _ := ret[0].([]Suggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Suggest indicates an expected call of Suggest.
+func (mr *MockStorerMockRecorder) Suggest(ctx, prefix, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Suggest", reflect.TypeOf((*MockStorer)(nil).Suggest), ctx, prefix, limit)
+}
+
+// UpdateFeed mocks base method.
+func (m *MockStorer) UpdateFeed(ctx context.Context, feed Feed) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFeed", ctx, feed)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateFeed indicates an expected call of UpdateFeed.
+func (mr *MockStorerMockRecorder) UpdateFeed(ctx, feed interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFeed", reflect.TypeOf((*MockStorer)(nil).UpdateFeed), ctx, feed)
+}
+
+// UpsertArticles mocks base method.
+func (m *MockStorer) UpsertArticles(ctx context.Context, articles []NewsArticle) (UpsertResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertArticles", ctx, articles)
+	ret0, _ := ret[0].(UpsertResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertArticles indicates an expected call of UpsertArticles.
+func (mr *MockStorerMockRecorder) UpsertArticles(ctx, articles interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecordsAfterID", reflect.TypeOf((*MockStorer)(nil).GetRecordsAfterID), ID, numberOfRecords, filters)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertArticles", reflect.TypeOf((*MockStorer)(nil).UpsertArticles), ctx, articles)
 }
@@ -1,3 +1,28 @@
 package store
 
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/moynur/news-app/internal/migrate"
+)
+
 // would add with more time!
+
+func TestMigrations(t *testing.T) {
+	for _, dir := range []string{postgresDialect.migrations, sqliteDialect.migrations} {
+		migrations, err := migrate.Load(migrationFiles, dir)
+		require.NoError(t, err, dir)
+		assert.NotEmpty(t, migrations, dir)
+		for i, migration := range migrations {
+			assert.Equal(t, i+1, migration.Version, "versions in %s should have no gaps", dir)
+		}
+	}
+}
+
+func TestSearchWords(t *testing.T) {
+	assert.Equal(t, []string{"storm", "hits", "the", "coast", "2026"}, searchWords(`  Storm "hits" the-coast? 2026 `))
+	assert.Empty(t, searchWords(` "-" `))
+}
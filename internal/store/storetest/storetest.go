@@ -0,0 +1,747 @@
+// Package storetest is the behaviour every store.Storer has to have, each backend runs it against an empty store
+package storetest
+
+import (
+	"context"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/moynur/news-app/internal/store"
+)
+
+// NewStore returns an empty store, anything it needs cleaning up afterwards should be registered with t.Cleanup
+type NewStore func(t *testing.T) store.Storer
+
+var published = time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC)
+
+// Run runs every conformance test against stores made by newStore
+func Run(t *testing.T, newStore NewStore) {
+	tests := map[string]func(t *testing.T, s store.Storer){
+		"feeds":                     testFeeds,
+		"feed states":               testFeedStates,
+		"upsert inserts and skips":  testUpsert,
+		"upsert updates changes":    testUpsertUpdates,
+		"upsert matches aliases":    testUpsertAliases,
+		"upsert records sources":    testUpsertSources,
+		"fallback thumbnail":        testFallbackThumbnail,
+		"categories":                testCategories,
+		"cursor and filters":        testCursorAndFilters,
+		"published order":           testPublishedOrder,
+		"descending and before":     testDescendingAndBefore,
+		"clusters":                  testClusters,
+		"search":                    testSearch,
+		"search relevance":          testSearchRelevance,
+		"conditions":                testConditions,
+		"fuzzy search":              testFuzzySearch,
+		"suggestions":               testSuggest,
+		"concurrent upserts":        testConcurrentUpserts,
+		"revisions of unknown item": testUnknownRevisions,
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			test(t, newStore(t))
+		})
+	}
+}
+
+func article(key string, title string) store.NewsArticle {
+	return store.NewsArticle{
+		Title:       title,
+		Description: title + " description",
+		Link:        "https://example.com/" + key,
+		LinkKey:     "example.com/" + key,
+		Thumbnail:   "https://example.com/" + key + ".jpg",
+		Source:      "example",
+		Sources:     []store.ArticleSource{{Source: "example", LinkKey: "example.com/" + key}},
+		Provider:    "Example News",
+		PublishedAt: published,
+		CreatedAt:   published,
+		// far enough apart that articles are never clustered unless a test says so
+		Fingerprint: fingerprint(key),
+	}
+}
+
+func fingerprint(key string) int64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(key))
+	return int64(hash.Sum64())
+}
+
+func upsert(t *testing.T, s store.Storer, articles ...store.NewsArticle) store.UpsertResult {
+	result, err := s.UpsertArticles(context.Background(), articles)
+	require.NoError(t, err)
+	return result
+}
+
+func records(t *testing.T, s store.Storer, cursor int, limit int, filters store.Filters) []store.NewsArticle {
+	found, err := s.GetRecordsAfterID(context.Background(), cursor, limit, filters)
+	require.NoError(t, err)
+	return found
+}
+
+// search is a condition for articles with every word of the text
+func search(text string) *store.Condition {
+	return &store.Condition{Kind: store.ConditionText, Value: text}
+}
+
+func titles(articles []store.NewsArticle) []string {
+	var names []string
+	for _, article := range articles {
+		names = append(names, article.Title)
+	}
+	return names
+}
+
+func testFeeds(t *testing.T, s store.Storer) {
+	ctx := context.Background()
+	feeds, err := s.GetFeeds(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, feeds)
+
+	require.NoError(t, s.CreateFeed(ctx, store.Feed{Name: "world", URL: "https://example.com/world.xml", RefreshIntervalSeconds: 60, Enabled: true}))
+	assert.ErrorIs(t, s.CreateFeed(ctx, store.Feed{Name: "world", URL: "https://example.com/other.xml"}), store.ErrAlreadyExists)
+	require.NoError(t, s.CreateFeedsIfNotExist(ctx, []store.Feed{
+		{Name: "world", URL: "https://example.com/ignored.xml"},
+		{Name: "uk", URL: "https://example.com/uk.xml", RefreshIntervalSeconds: 300, DefaultCategory: "uk"},
+	}))
+
+	feeds, err = s.GetFeeds(ctx)
+	require.NoError(t, err)
+	require.Len(t, feeds, 2)
+	assert.Equal(t, "uk", feeds[0].Name)
+	assert.Equal(t, "world", feeds[1].Name)
+	assert.Equal(t, "https://example.com/world.xml", feeds[1].URL)
+
+	require.NoError(t, s.UpdateFeed(ctx, store.Feed{Name: "world", URL: "https://example.com/new.xml", RefreshIntervalSeconds: 120, FallbackImage: "https://example.com/logo.png"}))
+	feed, err := s.GetFeed(ctx, "world")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/new.xml", feed.URL)
+	assert.Equal(t, 120, feed.RefreshIntervalSeconds)
+	assert.Equal(t, "https://example.com/logo.png", feed.FallbackImage)
+	assert.False(t, feed.Enabled, "zero values are written")
+	assert.ErrorIs(t, s.UpdateFeed(ctx, store.Feed{Name: "missing"}), store.ErrNotFound)
+
+	require.NoError(t, s.SaveFeedState(ctx, store.FeedState{Name: "world", ETag: `"v1"`}))
+	require.NoError(t, s.DeleteFeed(ctx, "world"))
+	_, err = s.GetFeed(ctx, "world")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+	assert.ErrorIs(t, s.DeleteFeed(ctx, "world"), store.ErrNotFound)
+	state, err := s.GetFeedState(ctx, "world")
+	require.NoError(t, err)
+	assert.Equal(t, store.FeedState{Name: "world"}, state, "the feed's state is deleted with it")
+
+	// a poll which finishes after its feed was deleted doesn't give the feed its state back
+	assert.ErrorIs(t, s.SaveFeedState(ctx, store.FeedState{Name: "world", ETag: `"v2"`}), store.ErrNotFound)
+	states, err := s.GetFeedStates(ctx)
+	require.NoError(t, err)
+	for _, state := range states {
+		assert.NotEqual(t, "world", state.Name)
+	}
+}
+
+func testFeedStates(t *testing.T, s store.Storer) {
+	ctx := context.Background()
+	state, err := s.GetFeedState(ctx, "never-fetched")
+	require.NoError(t, err)
+	assert.Equal(t, store.FeedState{Name: "never-fetched"}, state)
+	require.NoError(t, s.CreateFeedsIfNotExist(ctx, []store.Feed{
+		{Name: "world", URL: "https://example.com/world.xml"},
+		{Name: "uk", URL: "https://example.com/uk.xml"},
+	}))
+
+	next := published.Add(time.Hour)
+	saved := store.FeedState{
+		Name:                "world",
+		ETag:                `"v1"`,
+		LastModified:        "Mon, 02 Jan 2026 15:04:05 GMT",
+		TTLMinutes:          15,
+		SkipHours:           "3",
+		LastFetchedAt:       &published,
+		NextFetchAt:         &next,
+		ConsecutiveFailures: 10,
+		LastError:           "timeout",
+		Paused:              true,
+		LastStatus:          500,
+		LastItemsSeen:       3,
+	}
+	require.NoError(t, s.SaveFeedState(ctx, saved))
+	saved.LastItemsSeen = 4
+	require.NoError(t, s.SaveFeedState(ctx, saved))
+	require.NoError(t, s.SaveFeedState(ctx, store.FeedState{Name: "uk"}))
+
+	state, err = s.GetFeedState(ctx, "world")
+	require.NoError(t, err)
+	assert.Equal(t, 4, state.LastItemsSeen)
+	assert.Equal(t, `"v1"`, state.ETag)
+	assert.True(t, next.Equal(*state.NextFetchAt))
+
+	require.NoError(t, s.ResumeFeed(ctx, "world"))
+	state, err = s.GetFeedState(ctx, "world")
+	require.NoError(t, err)
+	assert.False(t, state.Paused)
+	assert.Zero(t, state.ConsecutiveFailures)
+	assert.Nil(t, state.NextFetchAt)
+	assert.Equal(t, "timeout", state.LastError)
+
+	states, err := s.GetFeedStates(ctx)
+	require.NoError(t, err)
+	require.Len(t, states, 2)
+	assert.Equal(t, "uk", states[0].Name)
+	assert.Equal(t, "world", states[1].Name)
+}
+
+func testUpsert(t *testing.T, s store.Storer) {
+	first := article("1", "First")
+	first.Authors = store.StringList{"Jane Smith"}
+	first.Enclosures = store.Enclosures{{URL: "https://example.com/1.mp3", Type: "audio/mpeg", Length: 1024}}
+	first.GUID = "urn:uuid:1"
+	first.Content = "<p>First</p>"
+	// the same link twice in a batch is only stored once
+	result := upsert(t, s, first, article("2", "Second"), first)
+	assert.Equal(t, store.UpsertResult{Inserted: 2, Unchanged: 1}, result)
+
+	result = upsert(t, s, first, article("2", "Second"), article("3", "Third"))
+	assert.Equal(t, store.UpsertResult{Inserted: 1, Unchanged: 2}, result)
+
+	found := records(t, s, 0, 10, store.Filters{})
+	require.Len(t, found, 3)
+	assert.Equal(t, []string{"First", "Second", "Third"}, titles(found))
+	stored := found[0]
+	assert.NotZero(t, stored.ID)
+	assert.Equal(t, first.Link, stored.Link)
+	assert.Equal(t, first.LinkKey, stored.LinkKey)
+	assert.Equal(t, first.GUID, stored.GUID)
+	assert.Equal(t, first.Content, stored.Content)
+	assert.Equal(t, first.Authors, stored.Authors)
+	assert.Equal(t, first.Enclosures, stored.Enclosures)
+	assert.Equal(t, first.Thumbnail, stored.Thumbnail)
+	assert.Equal(t, first.Provider, stored.Provider)
+	assert.True(t, first.PublishedAt.Equal(stored.PublishedAt))
+	assert.True(t, first.CreatedAt.Equal(stored.CreatedAt))
+	require.Len(t, stored.Sources, 1)
+	assert.Equal(t, "example", stored.Sources[0].Source)
+	assert.Equal(t, stored.ID, stored.Sources[0].ArticleID)
+}
+
+func testUpsertUpdates(t *testing.T, s store.Storer) {
+	ctx := context.Background()
+	upsert(t, s, article("1", "Original"))
+	stored := records(t, s, 0, 1, store.Filters{})[0]
+
+	changed := article("1", "Changed")
+	changed.Thumbnail = ""
+	updated := published.Add(time.Hour)
+	changed.SourceUpdatedAt = &updated
+	assert.Equal(t, store.UpsertResult{Updated: 1}, upsert(t, s, changed))
+	// nothing readers see has changed the second time
+	assert.Equal(t, store.UpsertResult{Unchanged: 1}, upsert(t, s, changed))
+
+	changed.Description = "new description"
+	changed.Thumbnail = "https://example.com/new.jpg"
+	assert.Equal(t, store.UpsertResult{Updated: 1}, upsert(t, s, changed))
+
+	found := records(t, s, 0, 10, store.Filters{})
+	require.Len(t, found, 1)
+	assert.Equal(t, "Changed", found[0].Title)
+	assert.Equal(t, "new description", found[0].Description)
+	assert.Equal(t, "https://example.com/new.jpg", found[0].Thumbnail)
+	assert.True(t, updated.Equal(*found[0].SourceUpdatedAt))
+
+	revisions, err := s.GetArticleRevisions(ctx, int(stored.ID))
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, "Changed", revisions[0].Title)
+	assert.Equal(t, "Changed description", revisions[0].Description)
+	assert.Equal(t, "https://example.com/1.jpg", revisions[0].Thumbnail, "an empty thumbnail keeps the existing one")
+	assert.Equal(t, "Original", revisions[1].Title)
+	assert.Equal(t, stored.ID, revisions[1].ArticleID)
+}
+
+func testUpsertAliases(t *testing.T, s store.Storer) {
+	amp := article("amp/1", "Story")
+	amp.LinkKey = "example.com/1"
+	upsert(t, s, amp)
+
+	// another feed links to the canonical page, and one links to the amp page
+	other := article("1", "Story")
+	other.Thumbnail = amp.Thumbnail
+	other.Sources = []store.ArticleSource{{Source: "other", LinkKey: "example.com/1"}}
+	assert.Equal(t, store.UpsertResult{Unchanged: 1}, upsert(t, s, other))
+	third := article("amp/1", "Story")
+	third.LinkKey = "example.com/elsewhere"
+	third.Sources = []store.ArticleSource{{Source: "third", LinkKey: "example.com/amp/1"}}
+	assert.Equal(t, store.UpsertResult{Unchanged: 1}, upsert(t, s, third))
+
+	found := records(t, s, 0, 10, store.Filters{})
+	require.Len(t, found, 1)
+	var sources []string
+	for _, source := range found[0].Sources {
+		sources = append(sources, source.Source)
+	}
+	assert.ElementsMatch(t, []string{"example", "other", "third"}, sources)
+}
+
+func testUpsertSources(t *testing.T, s store.Storer) {
+	first, second := article("1", "First"), article("2", "Second")
+	upsert(t, s, first, second)
+
+	// every article in the batch is recorded against the feed, a feed which has been recorded before is left as it is
+	batch := []store.NewsArticle{first, second}
+	for i := range batch {
+		batch[i].Sources = append(batch[i].Sources, store.ArticleSource{Source: "other", LinkKey: batch[i].LinkKey})
+	}
+	assert.Equal(t, store.UpsertResult{Unchanged: 2}, upsert(t, s, batch...))
+	assert.Equal(t, store.UpsertResult{Unchanged: 2}, upsert(t, s, batch...))
+
+	found := records(t, s, 0, 10, store.Filters{})
+	require.Len(t, found, 2)
+	for _, stored := range found {
+		var sources []string
+		for _, source := range stored.Sources {
+			sources = append(sources, source.Source)
+			assert.Equal(t, stored.ID, source.ArticleID)
+		}
+		assert.ElementsMatch(t, []string{"example", "other"}, sources, stored.Title)
+	}
+}
+
+func testFallbackThumbnail(t *testing.T, s store.Storer) {
+	require.NoError(t, s.CreateFeed(context.Background(), store.Feed{Name: "example", URL: "https://example.com/feed.xml", FallbackImage: "https://example.com/logo.png"}))
+	noImage := article("1", "No image")
+	noImage.Thumbnail = ""
+	upsert(t, s, noImage)
+	assert.Equal(t, "https://example.com/logo.png", records(t, s, 0, 1, store.Filters{})[0].Thumbnail)
+}
+
+func testCategories(t *testing.T, s store.Storer) {
+	politics := article("1", "Politics")
+	politics.Categories = []store.Category{{Name: " Politics "}, {Name: "UK"}}
+	world := article("2", "World")
+	world.Categories = []store.Category{{Name: "world"}, {Name: "politics"}}
+	upsert(t, s, politics, world, article("3", "Uncategorised"))
+
+	found := records(t, s, 0, 10, store.Filters{Categories: []string{"politics"}})
+	assert.Equal(t, []string{"Politics", "World"}, titles(found))
+	ids := map[string]uint{}
+	for _, category := range found[0].Categories {
+		ids[category.Name] = category.ID
+	}
+	assert.Len(t, ids, 2)
+	assert.Contains(t, ids, "uk")
+	for _, category := range found[1].Categories {
+		if category.Name == "politics" {
+			assert.Equal(t, ids["politics"], category.ID, "categories are only created once")
+		}
+	}
+
+	found = records(t, s, 0, 10, store.Filters{Categories: []string{"uk", "world"}})
+	assert.Equal(t, []string{"Politics", "World"}, titles(found))
+	found = records(t, s, 0, 10, store.Filters{Categories: []string{"politics", "world"}, MatchAllCategories: true})
+	assert.Equal(t, []string{"World"}, titles(found))
+}
+
+func testCursorAndFilters(t *testing.T, s store.Storer) {
+	var batch []store.NewsArticle
+	for i, title := range []string{"Alpha", "Bravo", "Charlie", "Delta", "Echo"} {
+		a := article(title, title)
+		a.CreatedAt = published.Add(time.Duration(i) * time.Hour)
+		a.PublishedAt = published.Add(time.Duration(i) * time.Hour)
+		if i%2 == 1 {
+			a.Provider = "Other News"
+		}
+		batch = append(batch, a)
+	}
+	upsert(t, s, batch...)
+
+	page := records(t, s, 0, 2, store.Filters{})
+	assert.Equal(t, []string{"Alpha", "Bravo"}, titles(page))
+	page = records(t, s, int(page[1].ID), 2, store.Filters{})
+	assert.Equal(t, []string{"Charlie", "Delta"}, titles(page))
+	page = records(t, s, int(page[1].ID), 2, store.Filters{})
+	assert.Equal(t, []string{"Echo"}, titles(page))
+	assert.Empty(t, records(t, s, int(page[0].ID), 2, store.Filters{}))
+
+	assert.Equal(t, []string{"Bravo", "Delta"}, titles(records(t, s, 0, 10, store.Filters{Provider: "Other News"})))
+	assert.Equal(t, []string{"Charlie"}, titles(records(t, s, 0, 10, store.Filters{Title: "Charlie"})))
+	assert.Equal(t, []string{"Charlie", "Delta"}, titles(records(t, s, 0, 10, store.Filters{Title: "%e%"})))
+	assert.Equal(t, []string{"Delta"}, titles(records(t, s, 0, 10, store.Filters{Title: "_elta"})))
+	assert.Empty(t, records(t, s, 0, 10, store.Filters{Title: "charlie"}), "like is case sensitive")
+	assert.Equal(t, []string{"Bravo"}, titles(records(t, s, 0, 10, store.Filters{Description: "Bravo%"})))
+	assert.Equal(t, []string{"Echo"}, titles(records(t, s, 0, 10, store.Filters{Link: "%/Echo"})))
+
+	after := published.Add(time.Hour)
+	before := published.Add(3 * time.Hour)
+	assert.Equal(t, []string{"Charlie"}, titles(records(t, s, 0, 10, store.Filters{CreatedAfter: &after, CreatedBefore: &before})))
+	assert.Equal(t, []string{"Charlie"}, titles(records(t, s, 0, 10, store.Filters{PublishedAfter: &after, PublishedBefore: &before})))
+	// the same times in another zone
+	zone := time.FixedZone("UTC+5", 5*60*60)
+	after, before = after.In(zone), before.In(zone)
+	assert.Equal(t, []string{"Charlie"}, titles(records(t, s, 0, 10, store.Filters{PublishedAfter: &after, PublishedBefore: &before})))
+}
+
+func testPublishedOrder(t *testing.T, s store.Storer) {
+	var batch []store.NewsArticle
+	// inserted in a different order to the one they were published in, two at the same time
+	for _, a := range []struct {
+		title  string
+		offset time.Duration
+	}{{"Third", 2 * time.Hour}, {"First", 0}, {"Second", time.Hour}, {"Also third", 2 * time.Hour}} {
+		next := article(a.title, a.title)
+		next.PublishedAt = published.Add(a.offset)
+		batch = append(batch, next)
+	}
+	upsert(t, s, batch...)
+
+	filters := store.Filters{Sort: store.SortPublished}
+	page := records(t, s, 0, 2, filters)
+	assert.Equal(t, []string{"First", "Second"}, titles(page))
+	page = records(t, s, int(page[1].ID), 2, filters)
+	assert.Equal(t, []string{"Third", "Also third"}, titles(page))
+	assert.Empty(t, records(t, s, int(page[1].ID), 2, filters))
+	assert.Empty(t, records(t, s, 9999, 2, filters), "an unknown cursor has nothing after it")
+}
+
+func testDescendingAndBefore(t *testing.T, s store.Storer) {
+	var batch []store.NewsArticle
+	// ingested A to E, published in a different order with two at the same time
+	for _, a := range []struct {
+		title  string
+		offset time.Duration
+	}{{"A", 3 * time.Hour}, {"B", 0}, {"C", 2 * time.Hour}, {"D", time.Hour}, {"E", 2 * time.Hour}} {
+		next := article(a.title, "Storm "+a.title)
+		next.PublishedAt = published.Add(a.offset)
+		batch = append(batch, next)
+	}
+	upsert(t, s, batch...)
+	all := records(t, s, 0, 10, store.Filters{})
+	require.Len(t, all, 5)
+	id := map[string]int{}
+	for _, found := range all {
+		id[strings.TrimPrefix(found.Title, "Storm ")] = int(found.ID)
+	}
+	page := func(cursor string, filters store.Filters) []string {
+		var names []string
+		for _, title := range titles(records(t, s, id[cursor], 2, filters)) {
+			names = append(names, strings.TrimPrefix(title, "Storm "))
+		}
+		return names
+	}
+
+	newest := store.Filters{Descending: true}
+	assert.Equal(t, []string{"E", "D"}, page("", newest))
+	assert.Equal(t, []string{"C", "B"}, page("D", newest))
+	assert.Equal(t, []string{"A"}, page("B", newest))
+	before := store.Filters{Descending: true, Before: true}
+	assert.Equal(t, []string{"D", "C"}, page("B", before), "the closest before the cursor, still newest first")
+	assert.Equal(t, []string{"E"}, page("D", before))
+	assert.Empty(t, page("E", before))
+	assert.Equal(t, []string{"B", "C"}, page("D", store.Filters{Before: true}))
+
+	latest := store.Filters{Sort: store.SortPublished, Descending: true}
+	assert.Equal(t, []string{"A", "E"}, page("", latest))
+	assert.Equal(t, []string{"C", "D"}, page("E", latest))
+	assert.Equal(t, []string{"B"}, page("D", latest))
+	assert.Equal(t, []string{"E", "C"}, page("D", store.Filters{Sort: store.SortPublished, Descending: true, Before: true}))
+	assert.Equal(t, []string{"D", "C"}, page("E", store.Filters{Sort: store.SortPublished, Before: true}))
+
+	relevance := store.Filters{Condition: search("storm"), Sort: store.SortRelevance, Descending: true, Before: true}
+	assert.Equal(t, []string{"A", "B"}, page("C", relevance), "the best matches are first however it's sorted")
+}
+
+func testClusters(t *testing.T, s store.Storer) {
+	story := article("1", "Storm hits the coast")
+	story.Fingerprint = 0x0f0f
+	sameStory := article("2", "Storm batters the coast")
+	sameStory.Fingerprint = 0x0f0e
+	sameStory.PublishedAt = published.Add(time.Hour)
+	tooLate := article("3", "Storm clean up begins")
+	tooLate.Fingerprint = 0x0f0f
+	tooLate.PublishedAt = published.Add(72 * time.Hour)
+	unrelated := article("4", "Election called")
+	unrelated.Fingerprint = -1
+	upsert(t, s, story, sameStory, tooLate, unrelated)
+
+	found := records(t, s, 0, 10, store.Filters{})
+	require.Len(t, found, 4)
+	assert.Equal(t, found[0].ID, found[0].ClusterID, "the first article about a story starts its cluster")
+	assert.Equal(t, found[0].ClusterID, found[1].ClusterID)
+	assert.Equal(t, found[2].ID, found[2].ClusterID)
+	assert.Equal(t, found[3].ID, found[3].ClusterID)
+	assert.Zero(t, found[0].Related, "related articles are only counted when collapsing")
+
+	collapsed := records(t, s, 0, 10, store.Filters{CollapseClusters: true})
+	assert.Equal(t, []string{"Storm hits the coast", "Storm clean up begins", "Election called"}, titles(collapsed))
+	assert.Equal(t, 1, collapsed[0].Related)
+	assert.Equal(t, 0, collapsed[1].Related)
+
+	// the first article to match the filters represents the story
+	collapsed = records(t, s, 0, 10, store.Filters{CollapseClusters: true, Title: "Storm batters%"})
+	assert.Equal(t, []string{"Storm batters the coast"}, titles(collapsed))
+	assert.Equal(t, 1, collapsed[0].Related)
+
+	// an article which is rewritten about another story moves to that story's cluster
+	sameStory.Title = "Election date set"
+	sameStory.Fingerprint = -2
+	upsert(t, s, sameStory)
+	found = records(t, s, 0, 10, store.Filters{})
+	require.Len(t, found, 4)
+	assert.Equal(t, found[0].ID, found[0].ClusterID)
+	assert.Equal(t, found[3].ClusterID, found[1].ClusterID)
+	collapsed = records(t, s, 0, 10, store.Filters{CollapseClusters: true})
+	assert.Equal(t, []string{"Storm hits the coast", "Election date set", "Storm clean up begins"}, titles(collapsed))
+	assert.Equal(t, 0, collapsed[0].Related)
+	assert.Equal(t, 1, collapsed[1].Related)
+
+	// articles whose fingerprints have no part in common aren't compared
+	apart := article("5", "Storm warning")
+	apart.Fingerprint = 0x0f0f ^ 0x0101010101010101
+	upsert(t, s, apart)
+	found = records(t, s, 0, 10, store.Filters{Title: "Storm warning"})
+	require.Len(t, found, 1)
+	assert.Equal(t, found[0].ID, found[0].ClusterID)
+}
+
+func testSearch(t *testing.T, s store.Storer) {
+	storm := article("storm", "Storm hits the coast")
+	storm.Description = "Thousands without power"
+	election := article("election", "Election results are in")
+	election.Content = "<p>Turnout was the highest since the storm</p>"
+	upsert(t, s, storm, election, article("markets", "Markets rally"))
+
+	search := func(text string) []string {
+		return titles(records(t, s, 0, 10, store.Filters{Condition: search(text)}))
+	}
+	assert.Equal(t, []string{"Storm hits the coast", "Election results are in"}, search("storm"))
+	assert.Equal(t, []string{"Storm hits the coast"}, search("STORM coast"))
+	assert.Equal(t, []string{"Storm hits the coast"}, search("power"), "the description is searched")
+	assert.Equal(t, []string{"Election results are in"}, search("turnout"), "the content is searched")
+	assert.Empty(t, search("storm markets"), "every word has to match")
+	assert.Empty(t, search("weather"))
+	assert.Len(t, search(` "*" `), 3, "a search without any words doesn't filter")
+
+	// searching follows the article when it changes
+	storm.Title = "Flooding closes the coast road"
+	upsert(t, s, storm)
+	assert.Equal(t, []string{"Election results are in"}, search("storm"))
+	assert.Equal(t, []string{"Flooding closes the coast road"}, search("flooding"))
+}
+
+func testSearchRelevance(t *testing.T, s store.Storer) {
+	inContent := article("content", "Weather")
+	inContent.Description = "Rain all week"
+	inContent.Content = "A recession in rainfall"
+	inDescription := article("description", "Markets")
+	inDescription.Description = "Fears of a recession grow"
+	inTitle := article("title", "Recession fears")
+	inTitle.Description = "Markets slide"
+	noDescription := article("none", "Inflation eases")
+	noDescription.Description = ""
+	// enough articles without the word that it isn't too common to count
+	upsert(t, s, inContent, inDescription, inTitle, noDescription, article("a", "Sport"), article("b", "Travel"),
+		article("c", "Science"), article("d", "Culture"))
+
+	filters := store.Filters{Condition: search("recession"), Sort: store.SortRelevance}
+	page := records(t, s, 0, 2, filters)
+	require.Len(t, page, 2)
+	assert.Equal(t, []string{"Recession fears", "Markets"}, titles(page))
+	page = records(t, s, int(page[1].ID), 2, filters)
+	assert.Equal(t, []string{"Weather"}, titles(page))
+	assert.Empty(t, records(t, s, int(page[0].ID), 2, filters))
+
+	byTitle := map[string]store.NewsArticle{}
+	for _, found := range records(t, s, 0, 10, filters) {
+		byTitle[found.Title] = found
+	}
+	assert.Contains(t, byTitle["Markets"].Headline, "<mark>recession</mark>")
+	assert.Equal(t, "Markets slide", byTitle["Recession fears"].Headline, "the headline is from the description")
+	found := records(t, s, 0, 10, store.Filters{Condition: search("inflation")})
+	require.Len(t, found, 1)
+	assert.Contains(t, found[0].Headline, "<mark>Inflation</mark>", "without a description it's the title")
+
+	assert.Equal(t, []string{"Weather", "Markets", "Recession fears"},
+		titles(records(t, s, 0, 10, store.Filters{Condition: search("recession")})), "searching is ordered by ID unless sorting by relevance")
+	assert.Equal(t, "", records(t, s, 0, 1, store.Filters{})[0].Headline, "there's only a headline when searching")
+}
+
+func testConditions(t *testing.T, s store.Storer) {
+	boe := article("boe", "Bank of England raises rates")
+	boe.Description = "Inflation is high"
+	boe.Provider = "Sky News"
+	boe.Categories = []store.Category{{Name: "business"}}
+	ecb := article("ecb", "European bank holds rates")
+	ecb.Description = "Inflation falls"
+	ecb.Provider = "BBC News"
+	ecb.Categories = []store.Category{{Name: "business"}}
+	ecb.PublishedAt = published.Add(time.Hour)
+	sport := article("sport", "England win the cup")
+	sport.Description = "A sport story for the bank holiday"
+	sport.Provider = "Sky News"
+	sport.Categories = []store.Category{{Name: "Sport"}}
+	sport.PublishedAt = published.Add(2 * time.Hour)
+	growth := article("growth", "100% growth_rate")
+	growth.Description = "Numbers"
+	growth.Provider = "Other"
+	growth.PublishedAt = published.Add(3 * time.Hour)
+	upsert(t, s, boe, ecb, sport, growth)
+
+	matching := func(c store.Condition) []string {
+		return titles(records(t, s, 0, 10, store.Filters{Condition: &c}))
+	}
+	text := func(kind store.ConditionKind, value string) store.Condition {
+		return store.Condition{Kind: kind, Value: value}
+	}
+	assert.Equal(t, []string{"Bank of England raises rates"}, matching(text(store.ConditionTitle, "bank OF england")))
+	assert.Equal(t, []string{"Bank of England raises rates"}, matching(text(store.ConditionPhrase, "bank of england")))
+	assert.Equal(t, []string{"Bank of England raises rates", "European bank holds rates", "England win the cup"},
+		matching(text(store.ConditionText, "bank")))
+	assert.Equal(t, []string{"Bank of England raises rates"}, matching(store.Condition{Kind: store.ConditionAll, Conditions: []store.Condition{
+		{Kind: store.ConditionAny, Conditions: []store.Condition{text(store.ConditionText, "inflation"), text(store.ConditionText, "rates")}},
+		{Kind: store.ConditionNot, Conditions: []store.Condition{text(store.ConditionText, "sport")}},
+		text(store.ConditionProvider, "sky"),
+	}}))
+	assert.Equal(t, []string{"European bank holds rates", "England win the cup"}, matching(store.Condition{Kind: store.ConditionAny, Conditions: []store.Condition{
+		text(store.ConditionCategory, "SPORT"),
+		text(store.ConditionProvider, "bbc"),
+	}}))
+	assert.Equal(t, []string{"European bank holds rates", "England win the cup"}, matching(store.Condition{Kind: store.ConditionAll, Conditions: []store.Condition{
+		{Kind: store.ConditionPublishedAfter, Time: published.Add(30 * time.Minute)},
+		{Kind: store.ConditionPublishedBefore, Time: published.Add(150 * time.Minute)},
+	}}))
+	assert.Equal(t, []string{"England win the cup", "100% growth_rate"},
+		matching(store.Condition{Kind: store.ConditionNot, Conditions: []store.Condition{text(store.ConditionTitle, "bank")}}))
+	assert.Equal(t, "A sport story for the bank holiday", records(t, s, 0, 1, store.Filters{
+		Condition: &store.Condition{Kind: store.ConditionDescription, Value: "SPORT story"}})[0].Description)
+
+	// wildcards are matched as they are
+	assert.Equal(t, []string{"100% growth_rate"}, matching(text(store.ConditionTitle, "%")))
+	assert.Equal(t, []string{"100% growth_rate"}, matching(text(store.ConditionTitle, "_")))
+	assert.Empty(t, matching(text(store.ConditionTitle, `\`)))
+
+	// the words of text conditions are highlighted even when only one of them matched
+	found := records(t, s, 0, 10, store.Filters{Sort: store.SortRelevance, Condition: &store.Condition{
+		Kind: store.ConditionAny, Conditions: []store.Condition{text(store.ConditionText, "inflation"), text(store.ConditionText, "holiday")},
+	}})
+	require.Len(t, found, 3)
+	for _, article := range found {
+		assert.Contains(t, article.Headline, "<mark>", article.Title)
+	}
+}
+
+func testFuzzySearch(t *testing.T, s store.Storer) {
+	government := article("government", "Government announces budget")
+	government.Description = "Spending on schools rises"
+	inflation := article("inflation", "Inflation falls")
+	inflation.Description = "Prices rise more slowly"
+	markets := article("markets", "Markets rally")
+	markets.Description = "Shares in government bonds climb"
+	upsert(t, s, government, inflation, markets)
+
+	fuzzy := func(filters store.Filters) []string {
+		filters.Fuzzy = true
+		return titles(records(t, s, 0, 10, filters))
+	}
+	assert.Empty(t, records(t, s, 0, 10, store.Filters{Condition: search("goverment")}), "the misspelt word isn't found exactly")
+	assert.Equal(t, []string{"Government announces budget", "Markets rally"}, fuzzy(store.Filters{Condition: search("goverment")}))
+	assert.Equal(t, []string{"Inflation falls"}, fuzzy(store.Filters{Condition: search("INFLATON prices")}))
+	assert.Empty(t, fuzzy(store.Filters{Condition: search("inflaton schools")}), "every word still has to match")
+	assert.Empty(t, fuzzy(store.Filters{Condition: search("weather")}))
+	assert.Equal(t, []string{"Markets rally"}, fuzzy(store.Filters{Condition: &store.Condition{
+		Kind: store.ConditionAll, Conditions: []store.Condition{
+			{Kind: store.ConditionText, Value: "goverment"},
+			{Kind: store.ConditionNot, Conditions: []store.Condition{{Kind: store.ConditionPhrase, Value: "schools rises"}}},
+		},
+	}}), "conditions match similar words")
+
+	found := records(t, s, 0, 10, store.Filters{Condition: search("inflaton"), Fuzzy: true, Sort: store.SortRelevance})
+	require.Len(t, found, 1)
+	assert.Equal(t, "", found[0].Headline, "fuzzy matches aren't highlighted")
+	page := records(t, s, 0, 1, store.Filters{Condition: search("goverment"), Fuzzy: true, Sort: store.SortRelevance})
+	require.Len(t, page, 1)
+	next := records(t, s, int(page[0].ID), 1, store.Filters{Condition: search("goverment"), Fuzzy: true, Sort: store.SortRelevance})
+	assert.ElementsMatch(t, []string{"Government announces budget", "Markets rally"}, titles(append(page, next...)))
+}
+
+func testSuggest(t *testing.T, s store.Storer) {
+	ctx := context.Background()
+	budget := article("budget", "Government announces budget")
+	budget.Provider = "Sky News"
+	budget.Categories = []store.Category{{Name: "politics"}}
+	polls := article("polls", "Polls close in the election")
+	polls.Provider = "Politico"
+	polls.PublishedAt = published.Add(time.Hour)
+	polls.Categories = []store.Category{{Name: "uk politics"}}
+	wildcard := article("wildcard", "Rates up 100%")
+	wildcard.Provider = "Sky News"
+	upsert(t, s, budget, polls, wildcard)
+
+	suggestions, err := s.Suggest(ctx, " POL", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []store.Suggestion{
+		{Text: "politics", Kind: store.SuggestCategory},
+		{Text: "uk politics", Kind: store.SuggestCategory},
+		{Text: "Politico", Kind: store.SuggestProvider},
+		{Text: "Polls close in the election", Kind: store.SuggestTitle},
+	}, suggestions)
+
+	suggestions, err = s.Suggest(ctx, "sky", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []store.Suggestion{{Text: "Sky News", Kind: store.SuggestProvider}}, suggestions, "providers aren't repeated")
+
+	suggestions, err = s.Suggest(ctx, "nnounce", 10)
+	require.NoError(t, err)
+	assert.Empty(t, suggestions, "only the start of words match")
+
+	suggestions, err = s.Suggest(ctx, "100%", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []store.Suggestion{{Text: "Rates up 100%", Kind: store.SuggestTitle}}, suggestions)
+	suggestions, err = s.Suggest(ctx, "_", 10)
+	require.NoError(t, err)
+	assert.Empty(t, suggestions, "wildcards are matched as they are")
+
+	suggestions, err = s.Suggest(ctx, "p", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []store.Suggestion{
+		{Text: "politics", Kind: store.SuggestCategory},
+		{Text: "Politico", Kind: store.SuggestProvider},
+		{Text: "Polls close in the election", Kind: store.SuggestTitle},
+	}, suggestions, "the limit is for each kind")
+}
+
+func testConcurrentUpserts(t *testing.T, s store.Storer) {
+	var wg sync.WaitGroup
+	results := make([]store.UpsertResult, 4)
+	errs := make([]error, 4)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = s.UpsertArticles(context.Background(), []store.NewsArticle{article("1", "Shared"), article("2", "Shared too")})
+		}(i)
+	}
+	wg.Wait()
+	var total store.UpsertResult
+	for i := range results {
+		require.NoError(t, errs[i])
+		total.Inserted += results[i].Inserted
+		total.Updated += results[i].Updated
+		total.Unchanged += results[i].Unchanged
+	}
+	assert.Equal(t, store.UpsertResult{Inserted: 2, Unchanged: 6}, total)
+	assert.Len(t, records(t, s, 0, 10, store.Filters{}), 2)
+}
+
+func testUnknownRevisions(t *testing.T, s store.Storer) {
+	_, err := s.GetArticleRevisions(context.Background(), 9999)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+	upsert(t, s, article("1", "Unchanged"))
+	revisions, err := s.GetArticleRevisions(context.Background(), int(records(t, s, 0, 1, store.Filters{})[0].ID))
+	require.NoError(t, err)
+	assert.Empty(t, revisions)
+}
@@ -0,0 +1,71 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SuggestionKind is what a suggestion is the name of
+type SuggestionKind string
+
+const (
+	SuggestCategory SuggestionKind = "category"
+	SuggestProvider SuggestionKind = "provider"
+	SuggestTitle    SuggestionKind = "title"
+)
+
+// Suggestion is something which can be searched for that starts with what's been typed
+type Suggestion struct {
+	Text string
+	Kind SuggestionKind
+}
+
+// Suggest returns up to limit each of the categories, providers and titles with a word starting with the prefix,
+// ignoring case. Categories and providers are in alphabetical order and titles newest first
+func (s *Store) Suggest(ctx context.Context, prefix string, limit int) ([]Suggestion, error) {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	var suggestions []Suggestion
+	for _, source := range []struct {
+		kind   SuggestionKind
+		column string
+		query  *gorm.DB
+	}{
+		{
+			kind:   SuggestCategory,
+			column: "name",
+			query:  s.db.Model(&Category{}).Where(startsWordExpr("name", prefix)).Order("name"),
+		},
+		{
+			kind:   SuggestProvider,
+			column: "provider",
+			query: s.db.Model(&NewsArticle{}).Where(startsWordExpr("lower(news_articles.provider)", prefix)).
+				Group("provider").Order("provider"),
+		},
+		{
+			kind:   SuggestTitle,
+			column: "title",
+			query: s.db.Model(&NewsArticle{}).Where(startsWordExpr("lower(news_articles.title)", prefix)).
+				Group("title").Order("MAX(published_at) DESC").Order("title"),
+		},
+	} {
+		var found []string
+		resp := source.query.WithContext(ctx).Limit(limit).Pluck(source.column, &found)
+		if resp.Error != nil {
+			return nil, fmt.Errorf("unable to suggest %ss, %w", source.kind, resp.Error)
+		}
+		for _, text := range found {
+			suggestions = append(suggestions, Suggestion{Text: text, Kind: source.kind})
+		}
+	}
+	return suggestions, nil
+}
+
+// startsWordExpr matches the lower case column having a word starting with the prefix, which has to be lower case
+func startsWordExpr(column string, prefix string) clause.Expr {
+	pattern := escapeLike(prefix) + "%"
+	return gorm.Expr("("+column+" LIKE ? ESCAPE '\\' OR "+column+" LIKE ? ESCAPE '\\')", pattern, "% "+pattern)
+}
@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/moynur/news-app/internal/models"
+	"github.com/moynur/news-app/internal/service"
+)
+
+type CreateFeedReq struct {
+	ID              string `json:"id"`
+	URL             string `json:"url"`
+	RefreshInterval string `json:"refresh_interval,omitempty"`
+	DefaultCategory string `json:"default_category,omitempty"`
+	FallbackImage   string `json:"fallback_image,omitempty"`
+	// Enabled defaults to true so a new feed is polled straight away
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+type UpdateFeedReq struct {
+	URL             *string `json:"url,omitempty"`
+	RefreshInterval *string `json:"refresh_interval,omitempty"`
+	DefaultCategory *string `json:"default_category,omitempty"`
+	FallbackImage   *string `json:"fallback_image,omitempty"`
+	Enabled         *bool   `json:"enabled,omitempty"`
+}
+
+type Feed struct {
+	ID              string `json:"id"`
+	URL             string `json:"url"`
+	RefreshInterval string `json:"refresh_interval"`
+	DefaultCategory string `json:"default_category,omitempty"`
+	FallbackImage   string `json:"fallback_image,omitempty"`
+	Enabled         bool   `json:"enabled"`
+}
+
+type ImportOPMLResp struct {
+	Created []Feed          `json:"created"`
+	Skipped []string        `json:"skipped"`
+	Failed  []ImportFailure `json:"failed"`
+}
+
+type ImportFailure struct {
+	URL    string `json:"url"`
+	Reason string `json:"reason"`
+}
+
+// maxOPMLSize is far bigger than any real list of subscriptions
+const maxOPMLSize = 5 << 20
+
+// requireAdmin only lets requests through with the admin token as a bearer token
+func (h *Handler) requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		// the token has to be sent as a bearer token, a bare one isn't accepted
+		if h.adminToken == "" || !strings.HasPrefix(header, "Bearer ") {
+			errorUnauthorized(w, "unauthorized")
+			return
+		}
+		token := strings.TrimPrefix(header, "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) != 1 {
+			errorUnauthorized(w, "unauthorized")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (h *Handler) CreateFeed(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	var req CreateFeedReq
+	err := decoder.Decode(&req)
+	if err != nil {
+		log.Println("\n Unable to decode req", err)
+		errorBadRequest(w, "unable to decode request")
+		return
+	}
+	feed := models.Feed{
+		Name:            req.ID,
+		URL:             req.URL,
+		DefaultCategory: req.DefaultCategory,
+		FallbackImage:   req.FallbackImage,
+		Enabled:         req.Enabled == nil || *req.Enabled,
+	}
+	if req.RefreshInterval != "" {
+		feed.RefreshInterval, err = time.ParseDuration(req.RefreshInterval)
+		if err != nil {
+			errorBadRequest(w, "refresh interval must be a duration like 5m")
+			return
+		}
+	}
+	feed, err = h.service.CreateFeed(r.Context(), feed)
+	if err != nil {
+		writeFeedError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	writeFeed(w, feed)
+}
+
+func (h *Handler) UpdateFeed(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	var req UpdateFeedReq
+	err := decoder.Decode(&req)
+	if err != nil {
+		log.Println("\n Unable to decode req", err)
+		errorBadRequest(w, "unable to decode request")
+		return
+	}
+	update := models.FeedUpdate{
+		URL:             req.URL,
+		DefaultCategory: req.DefaultCategory,
+		FallbackImage:   req.FallbackImage,
+		Enabled:         req.Enabled,
+	}
+	if req.RefreshInterval != nil {
+		interval, err := time.ParseDuration(*req.RefreshInterval)
+		if err != nil {
+			errorBadRequest(w, "refresh interval must be a duration like 5m")
+			return
+		}
+		update.RefreshInterval = &interval
+	}
+	feed, err := h.service.UpdateFeed(r.Context(), mux.Vars(r)["id"], update)
+	if err != nil {
+		writeFeedError(w, err)
+		return
+	}
+	writeFeed(w, feed)
+}
+
+func (h *Handler) DeleteFeed(w http.ResponseWriter, r *http.Request) {
+	err := h.service.DeleteFeed(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		writeFeedError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ImportOPML adds the feeds in the OPML document sent as the body
+func (h *Handler) ImportOPML(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	result, err := h.service.ImportOPML(r.Context(), http.MaxBytesReader(w, r.Body, maxOPMLSize))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidOPML):
+			errorBadRequest(w, err.Error())
+		default:
+			log.Println("unable to import opml", err)
+			errorUnknownFailure(w, "failed to import feeds")
+		}
+		return
+	}
+	response := ImportOPMLResp{
+		Created: []Feed{},
+		Skipped: []string{},
+		Failed:  []ImportFailure{},
+	}
+	for _, feed := range result.Created {
+		response.Created = append(response.Created, mapFeed(feed))
+	}
+	response.Skipped = append(response.Skipped, result.Skipped...)
+	for _, failure := range result.Failed {
+		response.Failed = append(response.Failed, ImportFailure{URL: failure.URL, Reason: failure.Reason})
+	}
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		log.Println("failure to write resp", err)
+	}
+}
+
+// ExportOPML returns every feed as an OPML document
+func (h *Handler) ExportOPML(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+	err := h.service.ExportOPML(r.Context(), &buf)
+	if err != nil {
+		log.Println("unable to export opml", err)
+		errorUnknownFailure(w, "failed to export feeds")
+		return
+	}
+	w.Header().Set("Content-Type", "text/x-opml; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="feeds.opml"`)
+	_, err = buf.WriteTo(w)
+	if err != nil {
+		log.Println("failure to write resp", err)
+	}
+}
+
+func writeFeed(w http.ResponseWriter, feed models.Feed) {
+	err := json.NewEncoder(w).Encode(mapFeed(feed))
+	if err != nil {
+		log.Println("failure to write resp", err)
+	}
+}
+
+func mapFeed(feed models.Feed) Feed {
+	return Feed{
+		ID:              feed.Name,
+		URL:             feed.URL,
+		RefreshInterval: feed.RefreshInterval.String(),
+		DefaultCategory: feed.DefaultCategory,
+		FallbackImage:   feed.FallbackImage,
+		Enabled:         feed.Enabled,
+	}
+}
+
+func writeFeedError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidFeed):
+		errorBadRequest(w, err.Error())
+	case errors.Is(err, service.ErrFeedNotFound):
+		errorNotFound(w, "feed not found")
+	case errors.Is(err, service.ErrFeedExists):
+		errorConflict(w, "feed already exists")
+	default:
+		log.Println("unable to manage feed", err)
+		errorUnknownFailure(w, "failed to manage feed")
+	}
+}
@@ -0,0 +1,168 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moynur/news-app/internal/models"
+	"github.com/moynur/news-app/internal/service"
+	handler "github.com/moynur/news-app/internal/transport/http"
+)
+
+const adminToken = "some token"
+
+func newAdminRequest(t *testing.T, method string, url string, body interface{}) *http.Request {
+	var reqMarshalled []byte
+	if body != nil {
+		var err error
+		reqMarshalled, err = json.Marshal(body)
+		assert.NoError(t, err)
+	}
+	r := httptest.NewRequest(method, url, bytes.NewReader(reqMarshalled))
+	r.Header.Set("Authorization", "Bearer "+adminToken)
+	return r
+}
+
+func TestHandler_Admin(t *testing.T) {
+	t.Run("should reject requests without the admin token", func(t *testing.T) {
+		for _, token := range []string{"", adminToken} {
+			ctrl := gomock.NewController(t)
+			ms := service.NewMockService(ctrl)
+			h, err := handler.NewHandler(ms, token)
+			assert.NoError(t, err)
+			r := mux.NewRouter()
+			h.ApplyRoutes(r)
+
+			req := httptest.NewRequest(http.MethodDelete, "/admin/feeds/bbc-news", nil)
+			req.Header.Set("Authorization", "Bearer wrong token")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusUnauthorized, w.Result().StatusCode, fmt.Sprintf("admin token %q", token))
+		}
+	})
+
+	t.Run("should reject the admin token without the bearer scheme", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := service.NewMockService(ctrl)
+		h, err := handler.NewHandler(ms, adminToken)
+		assert.NoError(t, err)
+		r := mux.NewRouter()
+		h.ApplyRoutes(r)
+
+		req := httptest.NewRequest(http.MethodDelete, "/admin/feeds/bbc-news", nil)
+		req.Header.Set("Authorization", adminToken)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
+	})
+
+	t.Run("should create a feed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := service.NewMockService(ctrl)
+		h, err := handler.NewHandler(ms, adminToken)
+		assert.NoError(t, err)
+		r := mux.NewRouter()
+		h.ApplyRoutes(r)
+
+		feed := models.Feed{Name: "bbc-news", URL: "https://feeds.bbci.co.uk/news/rss.xml", RefreshInterval: 5 * time.Minute, Enabled: true}
+		ms.EXPECT().CreateFeed(gomock.Any(), feed).Return(feed, nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, newAdminRequest(t, http.MethodPost, "/admin/feeds", handler.CreateFeedReq{
+			ID:              "bbc-news",
+			URL:             "https://feeds.bbci.co.uk/news/rss.xml",
+			RefreshInterval: "5m",
+		}))
+		resp := w.Result()
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var out handler.Feed
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+		assert.Equal(t, handler.Feed{ID: "bbc-news", URL: "https://feeds.bbci.co.uk/news/rss.xml", RefreshInterval: "5m0s", Enabled: true}, out)
+	})
+
+	t.Run("should return bad request for an invalid feed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := service.NewMockService(ctrl)
+		h, err := handler.NewHandler(ms, adminToken)
+		assert.NoError(t, err)
+		r := mux.NewRouter()
+		h.ApplyRoutes(r)
+
+		ms.EXPECT().CreateFeed(gomock.Any(), gomock.Any()).Return(models.Feed{}, fmt.Errorf("%w: bad url", service.ErrInvalidFeed))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, newAdminRequest(t, http.MethodPost, "/admin/feeds", handler.CreateFeedReq{ID: "bbc-news", URL: "bbc"}))
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	t.Run("should pause a feed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := service.NewMockService(ctrl)
+		h, err := handler.NewHandler(ms, adminToken)
+		assert.NoError(t, err)
+		r := mux.NewRouter()
+		h.ApplyRoutes(r)
+
+		disabled := false
+		ms.EXPECT().UpdateFeed(gomock.Any(), "bbc-news", models.FeedUpdate{Enabled: &disabled}).
+			Return(models.Feed{Name: "bbc-news", RefreshInterval: time.Minute}, nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, newAdminRequest(t, http.MethodPatch, "/admin/feeds/bbc-news", handler.UpdateFeedReq{Enabled: &disabled}))
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	})
+
+	t.Run("should delete a feed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := service.NewMockService(ctrl)
+		h, err := handler.NewHandler(ms, adminToken)
+		assert.NoError(t, err)
+		r := mux.NewRouter()
+		h.ApplyRoutes(r)
+
+		ms.EXPECT().DeleteFeed(gomock.Any(), "bbc-news").Return(service.ErrFeedNotFound)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, newAdminRequest(t, http.MethodDelete, "/admin/feeds/bbc-news", nil))
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+}
+
+func TestHandler_ImportOPML(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ms := service.NewMockService(ctrl)
+	h, err := handler.NewHandler(ms, adminToken)
+	assert.NoError(t, err)
+	r := mux.NewRouter()
+	h.ApplyRoutes(r)
+
+	ms.EXPECT().ImportOPML(gomock.Any(), gomock.Any()).Return(models.FeedImport{
+		Created: []models.Feed{{Name: "bbc-news", URL: "https://feeds.bbci.co.uk/news/rss.xml", RefreshInterval: time.Minute, Enabled: true}},
+		Skipped: []string{"http://feeds.skynews.com/feeds/rss/uk.xml"},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/feeds/opml", bytes.NewReader([]byte("<opml/>")))
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var out handler.ImportOPMLResp
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	assert.Equal(t, handler.ImportOPMLResp{
+		Created: []handler.Feed{{ID: "bbc-news", URL: "https://feeds.bbci.co.uk/news/rss.xml", RefreshInterval: "1m0s", Enabled: true}},
+		Skipped: []string{"http://feeds.skynews.com/feeds/rss/uk.xml"},
+		Failed:  []handler.ImportFailure{},
+	}, out)
+}
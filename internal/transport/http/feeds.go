@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/moynur/news-app/internal/models"
+	"github.com/moynur/news-app/internal/service"
+)
+
+type FeedStatus struct {
+	ID                  string     `json:"id"`
+	URL                 string     `json:"url"`
+	Enabled             bool       `json:"enabled"`
+	Paused              bool       `json:"paused"`
+	LastFetchedAt       *time.Time `json:"last_fetched_at,omitempty"`
+	LastSuccessAt       *time.Time `json:"last_success_at,omitempty"`
+	LastStatus          int        `json:"last_status,omitempty"`
+	ItemsSeen           int        `json:"items_seen"`
+	ItemsInserted       int        `json:"items_inserted"`
+	ItemsUpdated        int        `json:"items_updated"`
+	DuplicatesSkipped   int        `json:"duplicates_skipped"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastError           string     `json:"last_error,omitempty"`
+	NextRunAt           *time.Time `json:"next_run_at,omitempty"`
+}
+
+type GetFeedsResp struct {
+	Feeds []FeedStatus `json:"feeds"`
+}
+
+func (h *Handler) GetFeeds(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	feeds, err := h.service.GetFeeds(r.Context())
+	if err != nil {
+		log.Println("unable to get feeds", err)
+		errorUnknownFailure(w, "failed to fetch feeds")
+		return
+	}
+	response := GetFeedsResp{Feeds: []FeedStatus{}}
+	for _, feed := range feeds {
+		response.Feeds = append(response.Feeds, mapFeedStatus(feed))
+	}
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		log.Println("failure to write resp", err)
+		errorUnknownFailure(w, "unknown failure")
+		return
+	}
+}
+
+func (h *Handler) GetFeedStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	feed, err := h.service.GetFeedStatus(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		switch err {
+		case service.ErrFeedNotFound:
+			errorNotFound(w, "feed not found")
+		default:
+			log.Println("unable to get feed status", err)
+			errorUnknownFailure(w, "failed to fetch feed status")
+		}
+		return
+	}
+	err = json.NewEncoder(w).Encode(mapFeedStatus(feed))
+	if err != nil {
+		log.Println("failure to write resp", err)
+		errorUnknownFailure(w, "unknown failure")
+		return
+	}
+}
+
+func mapFeedStatus(feed models.FeedStatus) FeedStatus {
+	return FeedStatus{
+		ID:                  feed.Name,
+		URL:                 feed.URL,
+		Enabled:             feed.Enabled,
+		Paused:              feed.Paused,
+		LastFetchedAt:       feed.LastFetchedAt,
+		LastSuccessAt:       feed.LastSuccessAt,
+		LastStatus:          feed.LastStatus,
+		ItemsSeen:           feed.ItemsSeen,
+		ItemsInserted:       feed.ItemsInserted,
+		ItemsUpdated:        feed.ItemsUpdated,
+		DuplicatesSkipped:   feed.DuplicatesSkipped,
+		ConsecutiveFailures: feed.ConsecutiveFailures,
+		LastError:           feed.LastError,
+		NextRunAt:           feed.NextRunAt,
+	}
+}
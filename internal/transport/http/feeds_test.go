@@ -0,0 +1,104 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moynur/news-app/internal/models"
+	"github.com/moynur/news-app/internal/service"
+	handler "github.com/moynur/news-app/internal/transport/http"
+)
+
+func TestHandler_GetFeeds(t *testing.T) {
+	t.Run("should return the status of every feed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ms := service.NewMockService(ctrl)
+		h, err := handler.NewHandler(ms, "")
+		assert.NoError(t, err)
+
+		r := mux.NewRouter()
+		h.ApplyRoutes(r)
+
+		ms.EXPECT().GetFeeds(gomock.Any()).Return([]models.FeedStatus{
+			{Name: "sky-news-uk", URL: "http://sky/uk.xml", Enabled: true, LastStatus: 200, ItemsSeen: 10, ItemsInserted: 2, DuplicatesSkipped: 8},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/feeds", nil))
+		resp := w.Result()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var out handler.GetFeedsResp
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+		assert.Equal(t, handler.GetFeedsResp{Feeds: []handler.FeedStatus{
+			{ID: "sky-news-uk", URL: "http://sky/uk.xml", Enabled: true, LastStatus: 200, ItemsSeen: 10, ItemsInserted: 2, DuplicatesSkipped: 8},
+		}}, out)
+	})
+
+	t.Run("should handle a generic error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ms := service.NewMockService(ctrl)
+		h, err := handler.NewHandler(ms, "")
+		assert.NoError(t, err)
+
+		ms.EXPECT().GetFeeds(gomock.Any()).Return(nil, errors.New("something failed"))
+
+		w := httptest.NewRecorder()
+		h.GetFeeds(w, httptest.NewRequest(http.MethodGet, "/feeds", nil))
+		assert.Equal(t, http.StatusInternalServerError, w.Result().StatusCode)
+	})
+}
+
+func TestHandler_GetFeedStatus(t *testing.T) {
+	t.Run("should return the status of the feed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ms := service.NewMockService(ctrl)
+		h, err := handler.NewHandler(ms, "")
+		assert.NoError(t, err)
+
+		r := mux.NewRouter()
+		h.ApplyRoutes(r)
+
+		ms.EXPECT().GetFeedStatus(gomock.Any(), "sky-news-uk").Return(models.FeedStatus{Name: "sky-news-uk", LastError: "timeout"}, nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/feeds/sky-news-uk/status", nil))
+		resp := w.Result()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var out handler.FeedStatus
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+		assert.Equal(t, handler.FeedStatus{ID: "sky-news-uk", LastError: "timeout"}, out)
+	})
+
+	t.Run("should return not found for an unknown feed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ms := service.NewMockService(ctrl)
+		h, err := handler.NewHandler(ms, "")
+		assert.NoError(t, err)
+
+		r := mux.NewRouter()
+		h.ApplyRoutes(r)
+
+		ms.EXPECT().GetFeedStatus(gomock.Any(), "bbc").Return(models.FeedStatus{}, service.ErrFeedNotFound)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/feeds/bbc/status", nil))
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+}
@@ -3,8 +3,10 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 
@@ -13,36 +15,96 @@ import (
 )
 
 type LoadArticlesReq struct {
-	Cursor   int    `json:"cursor,omitempty"`
-	Category string `json:"category" json:"category,omitempty"`
-	Provider string `json:"provider,omitempty"`
-	Title    string `json:"title,omitempty"`
+	// Cursor is the next_cursor, prev_cursor or since_cursor of another page, it has to be sent with the same filters
+	// and sort
+	Cursor        string   `json:"cursor,omitempty"`
+	Category      string   `json:"category" json:"category,omitempty"`
+	Categories    []string `json:"categories,omitempty"`
+	CategoryMatch string   `json:"category_match,omitempty"`
+	Provider      string   `json:"provider,omitempty"`
+	Title         string   `json:"title,omitempty"`
+	// Query is a full text search such as "economy recession", every word has to be in the article. Terms can be
+	// combined with AND, OR, NOT and brackets and limited to a field like title:"bank of england"
+	Query string `json:"q,omitempty"`
+	// Sort is newest, oldest, published (newest published first), oldest_published or relevance, published_after and
+	// published_before are RFC 3339 times. It defaults to relevance when searching and newest when not
+	Sort            string     `json:"sort,omitempty"`
+	PublishedAfter  *time.Time `json:"published_after,omitempty"`
+	PublishedBefore *time.Time `json:"published_before,omitempty"`
+	// CollapseClusters returns one article per story, with related_count saying how many others cover it
+	CollapseClusters bool `json:"collapse_clusters,omitempty"`
 }
 
 type Article struct {
-	Title    string `json:"title,omitempty"`
-	Summary  string `json:"summary,omitempty"`
-	ImageRef string `json:"image_ref,omitempty"`
-	Link     string `json:"link,omitempty"`
+	// ID is what the article's revisions are found by
+	ID          int         `json:"id"`
+	Title       string      `json:"title,omitempty"`
+	Summary     string      `json:"summary,omitempty"`
+	Content     string      `json:"content,omitempty"`
+	ImageRef    string      `json:"image_ref,omitempty"`
+	Link        string      `json:"link,omitempty"`
+	GUID        string      `json:"guid,omitempty"`
+	Authors     []string    `json:"authors,omitempty"`
+	Enclosures  []Enclosure `json:"enclosures,omitempty"`
+	Provider    string      `json:"provider,omitempty"`
+	Sources     []string    `json:"sources,omitempty"`
+	Categories  []string    `json:"categories,omitempty"`
+	PublishedAt time.Time   `json:"published_at"`
+	UpdatedAt   *time.Time  `json:"updated_at,omitempty"`
+	IngestedAt  time.Time   `json:"ingested_at"`
+	ClusterID   int         `json:"cluster_id,omitempty"`
+	// RelatedCount is only set when collapsing clusters
+	RelatedCount int `json:"related_count,omitempty"`
+	// Snippet is only set when searching, the words which matched are wrapped in <mark> tags
+	Snippet string `json:"snippet,omitempty"`
+}
+
+type Enclosure struct {
+	URL    string `json:"url"`
+	Type   string `json:"type,omitempty"`
+	Length int64  `json:"length,omitempty"`
 }
 
 type LoadArticlesResp struct {
-	NextCursor int       `json:"next_cursor,omitempty"`
-	Articles   []Article `json:"articles,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	// SinceCursor loads the articles which have come in above the first of these, for pull to refresh
+	SinceCursor string    `json:"since_cursor,omitempty"`
+	Articles    []Article `json:"articles,omitempty"`
+	// Fuzzy is set when nothing matched the query as it was spelt, so the articles have words spelt like it instead
+	Fuzzy bool `json:"fuzzy,omitempty"`
+}
+
+// ErrorResp is the body of every error response
+type ErrorResp struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
 }
 
 type Handler struct {
 	service service.Service
+	// adminToken has to be sent as a bearer token to use the admin routes, they are disabled when it's empty
+	adminToken string
 }
 
-func NewHandler(svc service.Service) (*Handler, error) {
+func NewHandler(svc service.Service, adminToken string) (*Handler, error) {
 	return &Handler{
-		service: svc,
+		service:    svc,
+		adminToken: adminToken,
 	}, nil
 }
 
 func (h *Handler) ApplyRoutes(r *mux.Router) {
 	r.HandleFunc("/loadArticles", h.LoadArticles).Methods(http.MethodGet)
+	r.HandleFunc("/articles/{id}/revisions", h.GetArticleRevisions).Methods(http.MethodGet)
+	r.HandleFunc("/search/suggest", h.Suggest).Methods(http.MethodGet)
+	r.HandleFunc("/feeds", h.GetFeeds).Methods(http.MethodGet)
+	r.HandleFunc("/feeds/{id}/status", h.GetFeedStatus).Methods(http.MethodGet)
+	r.HandleFunc("/admin/feeds", h.requireAdmin(h.CreateFeed)).Methods(http.MethodPost)
+	r.HandleFunc("/admin/feeds/opml", h.requireAdmin(h.ImportOPML)).Methods(http.MethodPost)
+	r.HandleFunc("/admin/feeds/opml", h.requireAdmin(h.ExportOPML)).Methods(http.MethodGet)
+	r.HandleFunc("/admin/feeds/{id}", h.requireAdmin(h.UpdateFeed)).Methods(http.MethodPatch)
+	r.HandleFunc("/admin/feeds/{id}", h.requireAdmin(h.DeleteFeed)).Methods(http.MethodDelete)
 }
 
 func (h *Handler) LoadArticles(w http.ResponseWriter, r *http.Request) {
@@ -58,25 +120,25 @@ func (h *Handler) LoadArticles(w http.ResponseWriter, r *http.Request) {
 		errorBadRequest(w, "unable to decode request")
 		return
 	}
-	resp, err := h.service.GetArticles(mapRequest(newAuthRequest))
+	resp, err := h.service.GetArticles(r.Context(), mapRequest(newAuthRequest))
 	if err != nil {
-		switch err {
-		case service.ErrNotFound:
+		switch {
+		case err == service.ErrNotFound:
 			errorNotFound(w, "no articles found")
+		case err == service.ErrInvalidCategoryMatch, err == service.ErrInvalidSort, err == service.ErrRelevanceNeedsQuery,
+			err == service.ErrInvalidCursor, err == service.ErrCursorMismatch, errors.Is(err, service.ErrInvalidQuery):
+			errorBadRequest(w, err.Error())
 		default:
 			errorUnknownFailure(w, "failed to fetch articles")
 		}
 		return
 	}
-	log.Println(resp)
 	response.NextCursor = resp.NextCursor
+	response.PrevCursor = resp.PrevCursor
+	response.SinceCursor = resp.SinceCursor
+	response.Fuzzy = resp.Fuzzy
 	for _, article := range resp.Articles {
-		response.Articles = append(response.Articles, Article{
-			Title:    article.Title,
-			Summary:  article.Summary,
-			ImageRef: article.ImageRef,
-			Link:     article.Link,
-		})
+		response.Articles = append(response.Articles, mapArticle(article))
 	}
 	err = json.NewEncoder(w).Encode(response)
 	if err != nil {
@@ -92,12 +154,49 @@ func (h *Handler) GetArticle(w http.ResponseWriter, r *http.Request) {
 	//h.service.Getpege() for example
 }
 
+func mapArticle(article models.Article) Article {
+	mapped := Article{
+		ID:           article.ID,
+		Title:        article.Title,
+		Summary:      article.Summary,
+		Content:      article.Content,
+		ImageRef:     article.ImageRef,
+		Link:         article.Link,
+		GUID:         article.GUID,
+		Authors:      article.Authors,
+		Provider:     article.Provider,
+		Sources:      article.Sources,
+		Categories:   article.Categories,
+		PublishedAt:  article.PublishedAt,
+		UpdatedAt:    article.UpdatedAt,
+		IngestedAt:   article.IngestedAt,
+		ClusterID:    article.ClusterID,
+		RelatedCount: article.RelatedCount,
+		Snippet:      article.Snippet,
+	}
+	for _, enclosure := range article.Enclosures {
+		mapped.Enclosures = append(mapped.Enclosures, Enclosure{
+			URL:    enclosure.URL,
+			Type:   enclosure.Type,
+			Length: enclosure.Length,
+		})
+	}
+	return mapped
+}
+
 func mapRequest(req LoadArticlesReq) models.GetArticlesRequest {
 	return models.GetArticlesRequest{
-		Cursor:   req.Cursor,
-		Category: req.Category,
-		Provider: req.Provider,
-		Title:    req.Title,
+		Cursor:           req.Cursor,
+		Category:         req.Category,
+		Categories:       req.Categories,
+		CategoryMatch:    req.CategoryMatch,
+		Provider:         req.Provider,
+		Title:            req.Title,
+		Query:            req.Query,
+		Sort:             req.Sort,
+		PublishedAfter:   req.PublishedAfter,
+		PublishedBefore:  req.PublishedBefore,
+		CollapseClusters: req.CollapseClusters,
 	}
 }
 
@@ -113,17 +212,22 @@ func errorBadRequest(w http.ResponseWriter, message string) {
 	writeError(w, message, http.StatusBadRequest)
 }
 
+func errorUnauthorized(w http.ResponseWriter, message string) {
+	writeError(w, message, http.StatusUnauthorized)
+}
+
+func errorConflict(w http.ResponseWriter, message string) {
+	writeError(w, message, http.StatusConflict)
+}
+
 func errorUnprocessable(w http.ResponseWriter, message string) {
 	writeError(w, message, http.StatusUnprocessableEntity)
 }
 
 func writeError(w http.ResponseWriter, message string, code int) {
-	var err error
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(code)
-	enc := json.NewEncoder(w)
-
-	if encoderErr := enc.Encode(code); err != nil {
-		err = encoderErr
+	if err := json.NewEncoder(w).Encode(ErrorResp{Code: code, Message: message}); err != nil {
+		log.Println("unable to write error", err)
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -24,7 +25,7 @@ func TestHandler_NewHandler(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	ms := service.NewMockService(ctrl)
-	h, err := handler.NewHandler(ms)
+	h, err := handler.NewHandler(ms, "")
 	assert.NoError(t, err)
 	assert.NotNil(t, h)
 
@@ -32,21 +33,25 @@ func TestHandler_NewHandler(t *testing.T) {
 
 func TestHandler_LoadArticles(t *testing.T) {
 	t.Run("should return some articles", func(t *testing.T) {
+		published := time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC)
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		ms := service.NewMockService(ctrl)
 
-		h, err := handler.NewHandler(ms)
+		h, err := handler.NewHandler(ms, "")
 		assert.NoError(t, err)
 
 		assert.NotNil(t, h)
 
 		request := handler.LoadArticlesReq{
-			Cursor:   0,
-			Category: "some category",
-			Provider: "some provider",
-			Title:    "some title",
+			Cursor:           "some cursor",
+			Category:         "some category",
+			Provider:         "some provider",
+			Title:            "some title",
+			Query:            "economy recession",
+			Sort:             "published",
+			CollapseClusters: true,
 		}
 
 		reqMarshalled, err := json.Marshal(request)
@@ -56,14 +61,19 @@ func TestHandler_LoadArticles(t *testing.T) {
 		r := httptest.NewRequest(http.MethodGet, loadURL, bytes.NewReader(reqMarshalled))
 
 		expectedServerReq := models.GetArticlesRequest{
-			Cursor:   request.Cursor,
-			Category: request.Category,
-			Provider: request.Provider,
-			Title:    request.Title,
+			Cursor:           request.Cursor,
+			Category:         request.Category,
+			Provider:         request.Provider,
+			Title:            request.Title,
+			Query:            request.Query,
+			Sort:             request.Sort,
+			CollapseClusters: request.CollapseClusters,
 		}
 
 		expectedServerResp := models.GetArticlesResponse{
-			NextCursor: 0,
+			NextCursor:  "some next cursor",
+			PrevCursor:  "some prev cursor",
+			SinceCursor: "some since cursor",
 			Articles: []models.Article{
 				{
 					ID:       0,
@@ -71,18 +81,30 @@ func TestHandler_LoadArticles(t *testing.T) {
 					Summary:  "some summary",
 					ImageRef: "some image url",
 					Link:     "some page url",
+					Provider: "some provider",
 				},
 				{
-					ID:       1,
-					Title:    "some title",
-					Summary:  "some summary",
-					ImageRef: "some image url",
-					Link:     "some page url",
+					ID:           1,
+					Title:        "some title",
+					Summary:      "some summary",
+					Content:      "some content",
+					ImageRef:     "some image url",
+					Link:         "some page url",
+					GUID:         "some guid",
+					Authors:      []string{"some author"},
+					Enclosures:   []models.Enclosure{{URL: "some audio url", Type: "audio/mpeg", Length: 1024}},
+					Provider:     "some provider",
+					Sources:      []string{"some feed", "some other feed"},
+					PublishedAt:  published,
+					UpdatedAt:    &published,
+					ClusterID:    1,
+					RelatedCount: 2,
+					Snippet:      "some <mark>summary</mark>",
 				},
 			},
 		}
 
-		ms.EXPECT().GetArticles(expectedServerReq).Return(expectedServerResp, nil)
+		ms.EXPECT().GetArticles(gomock.Any(), expectedServerReq).Return(expectedServerResp, nil)
 
 		h.LoadArticles(w, r)
 		resp := w.Result()
@@ -91,19 +113,35 @@ func TestHandler_LoadArticles(t *testing.T) {
 		assert.NoError(t, err)
 
 		expected := handler.LoadArticlesResp{
-			NextCursor: 0, // the handler doesn't handle the cursor value just passes back what the service provides
+			// the handler doesn't handle the cursor values just passes back what the service provides
+			NextCursor:  "some next cursor",
+			PrevCursor:  "some prev cursor",
+			SinceCursor: "some since cursor",
 			Articles: []handler.Article{
 				{
 					Title:    "some title",
 					Summary:  "some summary",
 					ImageRef: "some image url",
 					Link:     "some page url",
+					Provider: "some provider",
 				},
 				{
-					Title:    "some title",
-					Summary:  "some summary",
-					ImageRef: "some image url",
-					Link:     "some page url",
+					ID:           1,
+					Title:        "some title",
+					Summary:      "some summary",
+					Content:      "some content",
+					ImageRef:     "some image url",
+					Link:         "some page url",
+					GUID:         "some guid",
+					Authors:      []string{"some author"},
+					Enclosures:   []handler.Enclosure{{URL: "some audio url", Type: "audio/mpeg", Length: 1024}},
+					Provider:     "some provider",
+					Sources:      []string{"some feed", "some other feed"},
+					PublishedAt:  published,
+					UpdatedAt:    &published,
+					ClusterID:    1,
+					RelatedCount: 2,
+					Snippet:      "some <mark>summary</mark>",
 				},
 			},
 		}
@@ -113,19 +151,40 @@ func TestHandler_LoadArticles(t *testing.T) {
 		assert.Equal(t, expected, out)
 	})
 
+	t.Run("should give each article's id so its revisions can be loaded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := service.NewMockService(ctrl)
+		h, err := handler.NewHandler(ms, "")
+		assert.NoError(t, err)
+
+		ms.EXPECT().GetArticles(gomock.Any(), models.GetArticlesRequest{}).
+			Return(models.GetArticlesResponse{Articles: []models.Article{{ID: 42, Title: "some title"}}}, nil)
+
+		w := httptest.NewRecorder()
+		h.LoadArticles(w, httptest.NewRequest(http.MethodGet, loadURL, bytes.NewReader([]byte("{}"))))
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+		var out struct {
+			Articles []map[string]interface{} `json:"articles"`
+		}
+		assert.NoError(t, json.NewDecoder(w.Result().Body).Decode(&out))
+		if assert.Len(t, out.Articles, 1) {
+			assert.Equal(t, float64(42), out.Articles[0]["id"])
+		}
+	})
+
 	t.Run("should handle an error when not found", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		ms := service.NewMockService(ctrl)
 
-		h, err := handler.NewHandler(ms)
+		h, err := handler.NewHandler(ms, "")
 		assert.NoError(t, err)
 
 		assert.NotNil(t, h)
 
 		request := handler.LoadArticlesReq{
-			Cursor:   0,
 			Category: "some category",
 			Provider: "some provider",
 			Title:    "some title",
@@ -146,7 +205,7 @@ func TestHandler_LoadArticles(t *testing.T) {
 
 		expectedServerResp := models.GetArticlesResponse{}
 
-		ms.EXPECT().GetArticles(expectedServerReq).Return(expectedServerResp, service.ErrNotFound)
+		ms.EXPECT().GetArticles(gomock.Any(), expectedServerReq).Return(expectedServerResp, service.ErrNotFound)
 
 		h.LoadArticles(w, r)
 		resp := w.Result()
@@ -159,13 +218,12 @@ func TestHandler_LoadArticles(t *testing.T) {
 
 		ms := service.NewMockService(ctrl)
 
-		h, err := handler.NewHandler(ms)
+		h, err := handler.NewHandler(ms, "")
 		assert.NoError(t, err)
 
 		assert.NotNil(t, h)
 
 		request := handler.LoadArticlesReq{
-			Cursor:   0,
 			Category: "some category",
 			Provider: "some provider",
 			Title:    "some title",
@@ -186,26 +244,107 @@ func TestHandler_LoadArticles(t *testing.T) {
 
 		expectedServerResp := models.GetArticlesResponse{}
 
-		ms.EXPECT().GetArticles(expectedServerReq).Return(expectedServerResp, errors.New("something failed"))
+		ms.EXPECT().GetArticles(gomock.Any(), expectedServerReq).Return(expectedServerResp, errors.New("something failed"))
 
 		h.LoadArticles(w, r)
 		resp := w.Result()
 		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 	})
 
+	t.Run("should return bad request when the category match is invalid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ms := service.NewMockService(ctrl)
+
+		h, err := handler.NewHandler(ms, "")
+		assert.NoError(t, err)
+
+		request := handler.LoadArticlesReq{
+			Categories:    []string{"uk"},
+			CategoryMatch: "some",
+		}
+
+		reqMarshalled, err := json.Marshal(request)
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, loadURL, bytes.NewReader(reqMarshalled))
+
+		ms.EXPECT().GetArticles(gomock.Any(), gomock.Any()).Return(models.GetArticlesResponse{}, service.ErrInvalidCategoryMatch)
+
+		h.LoadArticles(w, r)
+		resp := w.Result()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("should return bad request with the reason when the query is invalid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ms := service.NewMockService(ctrl)
+
+		h, err := handler.NewHandler(ms, "")
+		assert.NoError(t, err)
+
+		reqMarshalled, err := json.Marshal(handler.LoadArticlesReq{Query: "(inflation OR rates"})
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, loadURL, bytes.NewReader(reqMarshalled))
+
+		queryErr := &service.QueryError{Position: 1, Reason: "the ( is never closed"}
+		ms.EXPECT().GetArticles(gomock.Any(), models.GetArticlesRequest{Query: "(inflation OR rates"}).Return(models.GetArticlesResponse{}, queryErr)
+
+		h.LoadArticles(w, r)
+		resp := w.Result()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		var body handler.ErrorResp
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, handler.ErrorResp{
+			Code:    http.StatusBadRequest,
+			Message: "invalid query at character 1, the ( is never closed",
+		}, body)
+	})
+
+	t.Run("should return bad request when the cursor was for other filters", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ms := service.NewMockService(ctrl)
+
+		h, err := handler.NewHandler(ms, "")
+		assert.NoError(t, err)
+
+		reqMarshalled, err := json.Marshal(handler.LoadArticlesReq{Cursor: "some cursor", Provider: "other provider"})
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, loadURL, bytes.NewReader(reqMarshalled))
+
+		ms.EXPECT().GetArticles(gomock.Any(), models.GetArticlesRequest{Cursor: "some cursor", Provider: "other provider"}).
+			Return(models.GetArticlesResponse{}, service.ErrCursorMismatch)
+
+		h.LoadArticles(w, r)
+		resp := w.Result()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		var body handler.ErrorResp
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, service.ErrCursorMismatch.Error(), body.Message)
+	})
+
 	t.Run("should return error when request is bad", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		ms := service.NewMockService(ctrl)
 
-		h, err := handler.NewHandler(ms)
+		h, err := handler.NewHandler(ms, "")
 		assert.NoError(t, err)
 
 		assert.NotNil(t, h)
 
 		request := handler.LoadArticlesResp{
-			NextCursor: 0,
 			Articles: []handler.Article{
 				{
 					Title:    "",
@@ -222,7 +361,7 @@ func TestHandler_LoadArticles(t *testing.T) {
 		w := httptest.NewRecorder()
 		r := httptest.NewRequest(http.MethodGet, loadURL, bytes.NewReader(reqMarshalled))
 
-		ms.EXPECT().GetArticles(gomock.Any()).Times(0)
+		ms.EXPECT().GetArticles(gomock.Any(), gomock.Any()).Times(0)
 
 		h.LoadArticles(w, r)
 		resp := w.Result()
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/moynur/news-app/internal/service"
+)
+
+type ArticleRevision struct {
+	Title      string    `json:"title"`
+	Summary    string    `json:"summary,omitempty"`
+	ImageRef   string    `json:"image_ref,omitempty"`
+	ReplacedAt time.Time `json:"replaced_at"`
+}
+
+type GetArticleRevisionsResp struct {
+	Revisions []ArticleRevision `json:"revisions"`
+}
+
+// GetArticleRevisions returns what the article looked like before each time the publisher changed it, newest first
+func (h *Handler) GetArticleRevisions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		errorBadRequest(w, "article id must be a positive number")
+		return
+	}
+	revisions, err := h.service.GetArticleRevisions(r.Context(), id)
+	if err != nil {
+		switch err {
+		case service.ErrArticleNotFound:
+			errorNotFound(w, "article not found")
+		default:
+			log.Println("unable to get article revisions", err)
+			errorUnknownFailure(w, "failed to fetch article revisions")
+		}
+		return
+	}
+	response := GetArticleRevisionsResp{Revisions: []ArticleRevision{}}
+	for _, revision := range revisions {
+		response.Revisions = append(response.Revisions, ArticleRevision{
+			Title:      revision.Title,
+			Summary:    revision.Summary,
+			ImageRef:   revision.ImageRef,
+			ReplacedAt: revision.ReplacedAt,
+		})
+	}
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		log.Println("failure to write resp", err)
+		errorUnknownFailure(w, "unknown failure")
+		return
+	}
+}
@@ -0,0 +1,81 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moynur/news-app/internal/models"
+	"github.com/moynur/news-app/internal/service"
+	handler "github.com/moynur/news-app/internal/transport/http"
+)
+
+func TestHandler_GetArticleRevisions(t *testing.T) {
+	t.Run("should return the revisions", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ms := service.NewMockService(ctrl)
+		h, err := handler.NewHandler(ms, "")
+		assert.NoError(t, err)
+
+		r := mux.NewRouter()
+		h.ApplyRoutes(r)
+
+		replaced := time.Date(2026, 1, 2, 9, 30, 0, 0, time.UTC)
+		ms.EXPECT().GetArticleRevisions(gomock.Any(), 7).Return([]models.ArticleRevision{
+			{Title: "first headline", Summary: "summary", ReplacedAt: replaced},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/articles/7/revisions", nil))
+		resp := w.Result()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var out handler.GetArticleRevisionsResp
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+		assert.Equal(t, handler.GetArticleRevisionsResp{Revisions: []handler.ArticleRevision{
+			{Title: "first headline", Summary: "summary", ReplacedAt: replaced},
+		}}, out)
+	})
+
+	t.Run("should return not found for an unknown article", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ms := service.NewMockService(ctrl)
+		h, err := handler.NewHandler(ms, "")
+		assert.NoError(t, err)
+
+		r := mux.NewRouter()
+		h.ApplyRoutes(r)
+
+		ms.EXPECT().GetArticleRevisions(gomock.Any(), 8).Return(nil, service.ErrArticleNotFound)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/articles/8/revisions", nil))
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+
+	t.Run("should reject an id which isn't a number", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ms := service.NewMockService(ctrl)
+		h, err := handler.NewHandler(ms, "")
+		assert.NoError(t, err)
+
+		r := mux.NewRouter()
+		h.ApplyRoutes(r)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/articles/abc/revisions", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+}
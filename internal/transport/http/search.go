@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/moynur/news-app/internal/service"
+)
+
+type Suggestion struct {
+	Text string `json:"text"`
+	// Type is category, provider or title
+	Type string `json:"type"`
+}
+
+type SuggestResp struct {
+	Suggestions []Suggestion `json:"suggestions"`
+}
+
+// Suggest returns the categories, providers and titles with a word starting with the prefix query parameter, for
+// completing a search as it's typed
+func (h *Handler) Suggest(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	suggestions, err := h.service.Suggest(r.Context(), r.URL.Query().Get("prefix"))
+	if err != nil {
+		switch err {
+		case service.ErrInvalidPrefix:
+			errorBadRequest(w, err.Error())
+		default:
+			log.Println("unable to get suggestions", err)
+			errorUnknownFailure(w, "failed to fetch suggestions")
+		}
+		return
+	}
+	response := SuggestResp{Suggestions: []Suggestion{}}
+	for _, suggestion := range suggestions {
+		response.Suggestions = append(response.Suggestions, Suggestion{
+			Text: suggestion.Text,
+			Type: suggestion.Kind,
+		})
+	}
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		log.Println("failure to write resp", err)
+		errorUnknownFailure(w, "unknown failure")
+		return
+	}
+}
@@ -0,0 +1,86 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/moynur/news-app/internal/models"
+	"github.com/moynur/news-app/internal/service"
+	handler "github.com/moynur/news-app/internal/transport/http"
+)
+
+func TestHandler_Suggest(t *testing.T) {
+	t.Run("should return the suggestions", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ms := service.NewMockService(ctrl)
+		h, err := handler.NewHandler(ms, "")
+		assert.NoError(t, err)
+
+		r := mux.NewRouter()
+		h.ApplyRoutes(r)
+
+		ms.EXPECT().Suggest(gomock.Any(), "bank of").Return([]models.Suggestion{
+			{Text: "Bank of England raises rates", Kind: models.SuggestionTitle},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search/suggest?prefix=bank+of", nil))
+		resp := w.Result()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var out handler.SuggestResp
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+		assert.Equal(t, handler.SuggestResp{Suggestions: []handler.Suggestion{
+			{Text: "Bank of England raises rates", Type: "title"},
+		}}, out)
+	})
+
+	t.Run("should return an empty list when there are no suggestions", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ms := service.NewMockService(ctrl)
+		h, err := handler.NewHandler(ms, "")
+		assert.NoError(t, err)
+
+		r := mux.NewRouter()
+		h.ApplyRoutes(r)
+
+		ms.EXPECT().Suggest(gomock.Any(), "xyz").Return(nil, nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search/suggest?prefix=xyz", nil))
+		resp := w.Result()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var out map[string]interface{}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+		assert.Equal(t, map[string]interface{}{"suggestions": []interface{}{}}, out)
+	})
+
+	t.Run("should return bad request without a prefix", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ms := service.NewMockService(ctrl)
+		h, err := handler.NewHandler(ms, "")
+		assert.NoError(t, err)
+
+		r := mux.NewRouter()
+		h.ApplyRoutes(r)
+
+		ms.EXPECT().Suggest(gomock.Any(), "").Return(nil, service.ErrInvalidPrefix)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search/suggest", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+}